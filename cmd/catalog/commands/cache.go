@@ -0,0 +1,101 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"time"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/operations"
+	"github.com/spf13/cobra"
+)
+
+// defaultCacheMaxAge with the default age of the entries removed by cache prune.
+const defaultCacheMaxAge = 30 * 24 * time.Hour
+
+var cacheMaxAge time.Duration
+
+var cacheCmdLongHelp = `Manage the local cache of pulled applications.
+
+The applications pulled from the catalogs and their information are stored in ~/.napptive/cache,
+or in the directory set with --cacheDir. Tags other than latest are served from the cache without
+downloading them again. Use --refresh in pull to skip the cache, or --offline in pull and info to
+work without contacting the catalog.`
+
+var cacheCmdShortHelp = `Manage the local cache of pulled applications.`
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Long:  cacheCmdLongHelp,
+	Short: cacheCmdShortHelp,
+}
+
+var cacheListCmdLongHelp = `List the applications stored in the local cache.`
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Long:  cacheListCmdLongHelp,
+	Short: cacheListCmdLongHelp,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.CacheList())
+	},
+}
+
+var cachePruneCmdLongHelp = `Remove from the local cache the applications that have not been used in the given time.`
+
+var cachePruneCmd = &cobra.Command{
+	Use:     "prune",
+	Long:    cachePruneCmdLongHelp,
+	Short:   cachePruneCmdLongHelp,
+	Example: "$ catalog cache prune --max-age 168h",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.CachePrune(cacheMaxAge))
+	},
+}
+
+var cacheClearCmdLongHelp = `Remove all the content of the local cache.
+
+Only the refs and bundles directories of the cache are removed. Nothing is removed if the cache
+directory contains other files, which may happen if --cacheDir points to a directory by mistake.`
+
+var cacheClearCmdShortHelp = `Remove all the content of the local cache.`
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Long:  cacheClearCmdLongHelp,
+	Short: cacheClearCmdShortHelp,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.CacheClear())
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().DurationVar(&cacheMaxAge, "max-age", defaultCacheMaxAge, "Remove the applications not used in this duration")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
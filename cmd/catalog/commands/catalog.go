@@ -17,31 +17,101 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/diff"
 	"github.com/napptive/catalog-cli/v2/pkg/catalog/operations"
 	"github.com/spf13/cobra"
 )
 
 var privateApp bool
 var publicApp bool
+var visibilityOptions operations.BackupOptions
+var pushOptions operations.PushOptions
 
 var catalogPushCmdLongHelp = `Push an application in the catalog. \
-The application should be named: [catalog/]namespace/appName[:tag] `
+The application should be named: [catalog/]namespace/appName[:tag]
+
+The application path may be a directory, a .tgz, .tar.gz, .tar or .zip archive, or - to read
+a tar stream, optionally compressed with gzip, from the standard input. Archive entries with
+absolute paths or .. components are rejected.
+
+Before uploading, the digest of the application files is compared with the digest of the
+files of the tag in the catalog, and the push is skipped if both are identical and the
+visibility in the catalog is the requested one. The remote files are not downloaded if the
+digest recorded in the local cache when the tag was last pulled or pushed is different.
+Use --force to push the application anyway. Use --dry-run to list the files that would be pushed
+without contacting the catalog.
+
+Files matching the gitignore-style patterns of a .catalogignore file placed in the
+application directory are not pushed. The .git, .svn and .hg directories, .DS_Store
+and editor swap files are always excluded unless negated in that file.
+
+Symbolic links are followed by default, failing if a loop is found or if the target is outside
+the application directory unless --allow-external-symlinks is set. Use --symlinks=skip to ignore
+them or --symlinks=error to refuse pushing applications containing links.
+
+The same content can be pushed under several tags reading the application only once:
+
+$ catalog push namespace/app:1.4.2 ./app --tag 1.4 --tag latest
+
+Use --git-ref to push the files of a commit of the local git repository instead of the working
+tree. Committed symbolic links follow the --symlinks policy and must point inside the application.
+If the application has no tag and the reference is a git tag, it is used as the application tag:
+
+$ catalog push namespace/app repository/app --git-ref v1.2.0`
 
 var catalogPushCmdShortHelp = `Push an application in the catalog.`
 
 var pushCmd = &cobra.Command{
-	Use:   "push <[catalog/]namespace/appName[:tag]> <application_path>",
+	Use:   "push <[catalog/]namespace/appName[:tag]> <application_path|archive|->",
 	Long:  catalogPushCmdLongHelp,
 	Short: catalogPushCmdShortHelp,
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		catalog, err := operations.NewCatalog(&cfg)
 		crashOnError(err)
-		crashOnError(catalog.Push(args[0], args[1], privateApp))
+		crashOnError(catalog.Push(args[0], args[1], &pushOptions))
 	},
 }
 
-var catalogPullCmdLongHelp = `Pull an application from catalog.`
+var pullOptions operations.PullOptions
+
+var infoOptions operations.InfoOptions
+
+var catalogPullCmdLongHelp = `Pull an application from catalog.
+
+By default the application is saved as <appName>.tgz in the current directory. Use --dest to
+choose the file, or a directory in which <appName>.tgz is written. With --extract the application
+files are written in the --dest directory, ./<appName> by default, ready to be edited and pushed.
+Use --format tree to download the files uncompressed and write them in the --dest directory
+instead of receiving a tgz file.
+Existing files are never overwritten unless --force is set. The paths of the archive received from
+the catalog are checked before writing anything, refusing entries outside the target directory.
+
+The digest of the application content, the same one reported by push, is included in the result.
+Use --digest to fail if the received application does not match the expected digest. Use
+--lock-file to verify the digests pinned in a file, adding the applications that are not pinned yet.
+Nothing is written if the digest does not match.
+
+Use --dest - to write the tgz file to the standard output, the results and errors are printed on
+stderr.
+
+Several applications can be pulled at once, reading them from a file with one application per
+line or listing the applications of a namespace. Each application is written in
+<dest>/<namespace>/<appName>/<tag>.tgz and a summary is printed at the end. Applications listed
+more than once are skipped, and applications of different catalogs written in the same file fail:
+
+$ catalog pull --from-file apps.txt --dest ./snapshot
+$ catalog pull --namespace namespace --all-tags --dest ./snapshot
+$ catalog pull namespace/app:1.0 --dest ./downloads/
+$ catalog pull namespace/app:1.0 --dest - | tar -xz -C work/
+$ catalog pull namespace/app:1.0 --extract --dest ./app
+
+Pulled applications are stored in a local cache and tags other than latest are not downloaded
+again. Use --refresh to download the application anyway or --offline to only use the cache.`
 
 var catalogPullCmdShortHelp = `Pull an application from catalog.`
 
@@ -49,15 +119,89 @@ var pullCmd = &cobra.Command{
 	Use:   "pull <[catalog/]namespace/appName[:tag]>",
 	Long:  catalogPullCmdLongHelp,
 	Short: catalogPullCmdShortHelp,
-	Args:  cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if pullOptions.IsBulk() && len(args) > 0 {
+			return fmt.Errorf("no application can be given when --from-file or --namespace are used")
+		}
+		if pullOptions.IsBulk() {
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if pullOptions.Output == operations.StdoutPath {
+			redirectOutputToStderr()
+		}
 		catalog, err := operations.NewCatalog(&cfg)
 		crashOnError(err)
-		crashOnError(catalog.Pull(args[0]))
+		if pullOptions.IsBulk() {
+			crashOnError(catalog.PullAll(&pullOptions))
+			return
+		}
+		crashOnError(catalog.Pull(args[0], &pullOptions))
 	},
 }
 
-var catalogRemoveCmdLongHelp = `Remove an application from catalog.`
+var diffOptions operations.DiffOptions
+
+// diffErrorCode with the exit code of the diff command if the applications cannot be compared.
+const diffErrorCode = 2
+
+var catalogDiffCmdLongHelp = `Show the differences between an application stored in the catalog and a local application
+or another application, which may be stored in a different catalog.
+
+The second argument is a local application if the path exists, otherwise it must be an application
+of the catalog. A local application is read with the same rules used by push, so the result shows
+the changes that pushing it would introduce. The files added, removed and modified are listed, with
+a unified diff for the text files. Use --stat to only show the number of changed lines of each file.
+The changes of the application metadata, such as new required traits, scopes or Kubernetes entities,
+are shown separately.
+
+The exit code is 0 if the applications are identical, 1 if they are different and 2 if the
+comparison fails.
+
+$ catalog diff namespace/app:1.0 ./app
+$ catalog diff namespace/app:1.0 namespace/app:1.1 --stat
+$ catalog diff namespace/app:1.0 other.catalog.com/namespace/app:1.0`
+
+var catalogDiffCmdShortHelp = `Show the differences between two versions of an application.`
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <[catalog/]namespace/appName[:tag]> <application_path|[catalog/]namespace/appName[:tag]>",
+	Long:  catalogDiffCmdLongHelp,
+	Short: catalogDiffCmdShortHelp,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnErrorWithCode(err, diffErrorCode)
+		err = catalog.Diff(args[0], args[1], &diffOptions)
+		var differences *operations.DifferencesFoundError
+		if errors.As(err, &differences) {
+			os.Exit(1)
+		}
+		crashOnErrorWithCode(err, diffErrorCode)
+	},
+}
+
+var removeOptions operations.RemoveOptions
+
+var catalogRemoveCmdLongHelp = `Remove an application from catalog.
+
+If no tag is given, all the tags of the application are removed. When the command is run in a
+terminal, the application tags that will be removed are shown and the operation must be confirmed.
+Use --yes to skip the confirmation and --dry-run to only show the application tags. Use --backup to
+save the application tags in the local trash before removing them, see catalog restore.
+
+Use --namespace and --match to remove the application tags of a namespace matching a pattern with
+the format appName[:tag], where * and ? can be used as wildcards. All the tags of the matching
+applications are removed if the pattern does not contain a tag. The matching application tags are
+always shown and, unless --yes is set, the operation must be confirmed in a terminal.
+
+$ catalog remove namespace/app:1.0 --dry-run
+$ catalog remove namespace/app:1.0 --yes
+$ catalog remove namespace/app --backup
+$ catalog remove --namespace namespace --match 'feature-*'
+$ catalog remove --namespace namespace --match '*:feature-*' --yes`
 
 var catalogRemoveCmdShortHelp = `Remove an application from catalog.`
 
@@ -65,15 +209,69 @@ var removeCmd = &cobra.Command{
 	Use:   "remove <[catalog/]namespace/appName[:tag]>",
 	Long:  catalogRemoveCmdLongHelp,
 	Short: catalogRemoveCmdShortHelp,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if removeOptions.IsBulk() && len(args) > 0 {
+			return fmt.Errorf("no application can be given when --namespace is used")
+		}
+		if removeOptions.Match != "" && !removeOptions.IsBulk() {
+			return fmt.Errorf("--match requires --namespace")
+		}
+		if removeOptions.IsBulk() {
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		if removeOptions.IsBulk() {
+			crashOnError(catalog.RemoveAll(&removeOptions))
+			return
+		}
+		crashOnError(catalog.Remove(args[0], &removeOptions))
+	},
+}
+
+var pruneOptions operations.PruneOptions
+
+var catalogPruneCmdLongHelp = `Remove the oldest tags of an application keeping the most recent ones.
+
+Tags are ordered following semantic versioning, with an optional v prefix. Tags that are not versions
+are ordered lexically and considered older than any version. Use --keep-semver-latest-minor to also
+keep the latest patch of every minor version. Tags matching latest or v*-stable, and the patterns
+added with --protect, are never removed.
+
+When the command is run in a terminal, the tags that will be removed are shown and the operation must
+be confirmed. Use --yes to skip the confirmation and --dry-run to only show the plan. Use --backup to
+save the removed tags in the local trash, see catalog restore.
+
+$ catalog prune namespace/app --keep 10 --dry-run
+$ catalog prune namespace/app --keep 5 --keep-semver-latest-minor --protect 'release-*' --yes`
+
+var catalogPruneCmdShortHelp = `Remove the oldest tags of an application.`
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune <[catalog/]namespace/appName>",
+	Long:  catalogPruneCmdLongHelp,
+	Short: catalogPruneCmdShortHelp,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		catalog, err := operations.NewCatalog(&cfg)
 		crashOnError(err)
-		crashOnError(catalog.Remove(args[0]))
+		crashOnError(catalog.Prune(args[0], &pruneOptions))
 	},
 }
 
-var catalogInfoCmdLongHelp = `Get the principal information of an application.`
+var catalogInfoCmdLongHelp = `Get the principal information of an application.
+
+The information is stored in the local cache, use --offline to read it without contacting the catalog.
+Use --files to also list the files of the application with their sizes, or --file to print the content
+of one of them. The application is downloaded, or read from the local cache, to inspect its files.
+
+The README is rendered when the output is a terminal, use --no-readme to omit it.
+
+$ catalog info namespace/app:1.0 --files
+$ catalog info namespace/app:1.0 --file app/app.yaml`
 
 var catalogInfoCmdShortHelp = `Get the principal information of an application.`
 
@@ -85,7 +283,7 @@ var infoCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		catalog, err := operations.NewCatalog(&cfg)
 		crashOnError(err)
-		crashOnError(catalog.Info(args[0]))
+		crashOnError(catalog.Info(args[0], &infoOptions))
 	},
 }
 
@@ -145,13 +343,17 @@ var summaryCmd = &cobra.Command{
 	},
 }
 
-var catalogChangeVisibilityCmdLongHelp = `Update application visibility for all the application tags`
+var catalogChangeVisibilityCmdLongHelp = `Update application visibility for all the application tags.
+
+Use --backup to save all the application tags in the local trash before changing the visibility, so
+that they can be pushed again with their original visibility using catalog restore.`
 
 var catalogChangeVisibilityCmdShortHelp = `Update application visibility`
 
 var catalogChangeVisibilityCmdExample = `
 $ change-visibility <namespace>/<applicationName> --private
 $ change-visibility <namespace>/<applicationName> --public
+$ change-visibility <namespace>/<applicationName> --private --backup
 `
 
 var catalogChangeVisibilityCmd = &cobra.Command{
@@ -173,25 +375,94 @@ var catalogChangeVisibilityCmd = &cobra.Command{
 			public, err = cmd.Flags().GetBool("public")
 			crashOnError(err)
 		}
-		crashOnError(catalog.ChangeVisibility(args[0], private, public))
+		crashOnError(catalog.ChangeVisibility(args[0], private, public, &visibilityOptions))
+	},
+}
+
+var catalogLintCmdLongHelp = `Validate an application directory before pushing it to the catalog.
+The command checks that the application metadata is present and valid and that every YAML
+file can be parsed. No connection with the catalog is required. The command fails if any
+error is found.`
+
+var catalogLintCmdShortHelp = `Validate an application before pushing it.`
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <application_path>",
+	Long:  catalogLintCmdLongHelp,
+	Short: catalogLintCmdShortHelp,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.Lint(args[0]))
 	},
 }
 
 func init() {
 
-	pushCmd.Flags().BoolVar(&privateApp, "private", false, "Flag to indicate if an application is private")
+	pushCmd.Flags().BoolVar(&pushOptions.Private, "private", false, "Flag to indicate if an application is private")
+	pushCmd.Flags().StringVar(&pushOptions.Symlinks, "symlinks", operations.SymlinksFollow, "Policy applied to the symbolic links found in the application directory: follow, skip or error")
+	pushCmd.Flags().BoolVar(&pushOptions.AllowExternalSymlinks, "allow-external-symlinks", false, "Follow symbolic links whose target is outside the application directory")
+	pushCmd.Flags().StringVar(&pushOptions.GitRef, "git-ref", "", "Push the application files as they are in the given commit, branch or tag of the git repository containing the application path")
+	pushCmd.Flags().StringArrayVar(&pushOptions.Tags, "tag", []string{}, "Additional tag under which the application is pushed, can be repeated")
+	pushCmd.Flags().BoolVar(&pushOptions.DryRun, "dry-run", false, "Print the files that would be pushed without contacting the catalog")
+	pushCmd.Flags().BoolVar(&pushOptions.Force, "force", false, "Push the application even if the catalog already contains the same content")
+	pushCmd.Flags().IntVar(&pushOptions.MaxMessageSize, "maxMessageSize", operations.DefaultMaxMessageSize, "Maximum size in bytes of the messages accepted by the catalog, files that do not fit are rejected before pushing")
+
+	pullCmd.Flags().StringVarP(&pullOptions.Output, "dest", "d", "", "File or directory where the application is written, - for the standard output")
+	pullCmd.Flags().BoolVar(&pullOptions.Extract, "extract", false, "Write the application files in the destination directory instead of the tgz file")
+	pullCmd.Flags().StringVar(&pullOptions.Format, "format", operations.PullFormatTGZ, "Format in which the application is downloaded: tgz or tree")
+	pullCmd.Flags().BoolVar(&pullOptions.Force, "force", false, "Overwrite existing files")
+	pullCmd.Flags().StringVar(&pullOptions.Digest, "digest", "", "Expected digest of the application, sha256:<hex>")
+	pullCmd.Flags().StringVar(&pullOptions.FromFile, "from-file", "", "Pull the applications listed in a file, one per line")
+	pullCmd.Flags().StringVar(&pullOptions.Namespace, "namespace", "", "Pull the latest tag of every application of a namespace")
+	pullCmd.Flags().BoolVar(&pullOptions.AllTags, "all-tags", false, "Pull every tag of the applications of the namespace")
+	pullCmd.Flags().IntVar(&pullOptions.Workers, "workers", operations.DefaultPullWorkers, "Maximum number of applications downloaded concurrently")
+	pullCmd.Flags().StringVar(&pullOptions.LockFile, "lock-file", "", "File with the expected digests of the applications, missing applications are added once pulled")
+
+	pullCmd.Flags().BoolVar(&pullOptions.Offline, "offline", false, "Read the applications from the local cache without contacting the catalog")
+	pullCmd.Flags().BoolVar(&pullOptions.Refresh, "refresh", false, "Download the applications even if they are in the local cache")
+
+	removeCmd.Flags().BoolVarP(&removeOptions.Yes, "yes", "y", false, "Remove the application without asking for confirmation")
+	removeCmd.Flags().BoolVar(&removeOptions.DryRun, "dry-run", false, "Show the application tags that would be removed without removing them")
+	removeCmd.Flags().StringVar(&removeOptions.Namespace, "namespace", "", "Remove the application tags of a namespace matching --match")
+	removeCmd.Flags().StringVar(&removeOptions.Match, "match", "", "Pattern of the application tags to remove from the namespace: appName[:tag]")
+	removeCmd.Flags().IntVar(&removeOptions.Workers, "workers", operations.DefaultRemoveWorkers, "Maximum number of applications removed concurrently")
+	removeCmd.Flags().BoolVar(&removeOptions.Backup, "backup", false, "Save the application tags in the local trash before removing them")
+
+	pruneCmd.Flags().IntVar(&pruneOptions.Keep, "keep", operations.DefaultPruneKeep, "Number of most recent tags to keep")
+	pruneCmd.Flags().BoolVar(&pruneOptions.KeepLatestMinor, "keep-semver-latest-minor", false, "Keep the latest patch version of every minor version")
+	pruneCmd.Flags().StringArrayVar(&pruneOptions.Protect, "protect", []string{}, "Additional pattern of tags that are never removed, can be repeated")
+	pruneCmd.Flags().BoolVarP(&pruneOptions.Yes, "yes", "y", false, "Remove the tags without asking for confirmation")
+	pruneCmd.Flags().BoolVar(&pruneOptions.DryRun, "dry-run", false, "Show the tags that would be removed without removing them")
+	pruneCmd.Flags().BoolVar(&pruneOptions.Backup, "backup", false, "Save the tags in the local trash before removing them")
+
+	infoCmd.Flags().BoolVar(&infoOptions.Offline, "offline", false, "Read the information from the local cache without contacting the catalog")
+	infoCmd.Flags().BoolVar(&infoOptions.Files, "files", false, "List the files of the application with their sizes")
+	infoCmd.Flags().StringVar(&infoOptions.File, "file", "", "Print the content of a file of the application")
+	infoCmd.Flags().BoolVar(&infoOptions.NoReadme, "no-readme", false, "Omit the README of the application")
+	infoCmd.MarkFlagsMutuallyExclusive("files", "file")
+
+	diffCmd.Flags().BoolVar(&diffOptions.Stat, "stat", false, "Only show the number of changed lines of each file")
+	diffCmd.Flags().IntVarP(&diffOptions.Context, "unified", "U", diff.DefaultContext, "Number of unchanged lines shown around each change")
+	diffCmd.Flags().StringVar(&diffOptions.Symlinks, "symlinks", operations.SymlinksFollow, "Policy applied to the symbolic links found in the application directory: follow, skip or error")
+	diffCmd.Flags().BoolVar(&diffOptions.AllowExternalSymlinks, "allow-external-symlinks", false, "Follow symbolic links whose target is outside the application directory")
 
 	searchCmd.Flags().StringVarP(&targetNamespace, "namespace", "n", "", "Namespace to search for applications")
 
 	catalogChangeVisibilityCmd.Flags().BoolVar(&privateApp, "private", false, "Flag to indicate if an application becomes private")
 	catalogChangeVisibilityCmd.Flags().BoolVar(&publicApp, "public", true, "Flag to indicate if an application becomes public")
+	catalogChangeVisibilityCmd.Flags().BoolVar(&visibilityOptions.Backup, "backup", false, "Save the application tags in the local trash before changing the visibility")
 
 	rootCmd.AddCommand(pushCmd)
 	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(pruneCmd)
 	rootCmd.AddCommand(infoCmd)
 	rootCmd.AddCommand(summaryCmd)
 	rootCmd.AddCommand(catalogChangeVisibilityCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(lintCmd)
 }
@@ -0,0 +1,40 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+// commandArgsEnv with the environment variable that makes the test binary run a command instead of
+// the specs, so commands that exit the process can be tested.
+const commandArgsEnv = "CATALOG_TEST_COMMAND_ARGS"
+
+func TestCommandsPackage(t *testing.T) {
+	if args := os.Getenv(commandArgsEnv); args != "" {
+		rootCmd.SetArgs(strings.Split(args, "\n"))
+		Execute("test", "test")
+		os.Exit(0)
+	}
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Commands package suite")
+}
@@ -18,6 +18,7 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/user"
 
@@ -47,6 +48,9 @@ var DefaultConfigLocation = []string{"."}
 var debugLevel bool
 var consoleLogging bool
 
+// errorOutput with the writer where the errors are printed.
+var errorOutput io.Writer = os.Stdout
+
 var rootCmdLongHelp = "The catalog command provides a set of methods to interact with the Napptive Catalog"
 var rootCmdShortHelp = "Catalog command"
 var rootCmdExample = `$ catalog`
@@ -78,6 +82,9 @@ func init() {
 
 	rootCmd.PersistentFlags().BoolVar(&cfg.SkipCertValidation, "skipCertValidation", false, "enables ignoring the validation step of the certificate presented by the server")
 	rootCmd.PersistentFlags().BoolVar(&cfg.UseTLS, "useTLS", true, "TLS connection is expected with the Catalog manager")
+	rootCmd.PersistentFlags().StringVar(&cfg.CacheDir, "cacheDir", "", "Directory of the local cache of pulled applications, ~/.napptive/cache by default")
+	rootCmd.PersistentFlags().StringVar(&cfg.TrashDir, "trashDir", "", "Directory where the applications are saved before removing them, ~/.napptive/trash by default")
+	rootCmd.PersistentFlags().DurationVar(&cfg.TrashRetention, "trashRetention", defaultTrashRetention, "Time the saved applications are kept in the trash, 0 to keep them forever")
 	rootCmd.PersistentFlags().BoolVar(&cfg.UsePlaygroundConfiguration, "usePlaygroundConfiguration", true, "Set to false to avoid reading the .playground.yaml file")
 }
 
@@ -191,8 +198,22 @@ func readConfiguration() {
 
 // crashOnError prints the error if found and returns a non-zero value as the result of the playground CLI execution.
 func crashOnError(err error) {
+	crashOnErrorWithCode(err, 1)
+}
+
+// crashOnErrorWithCode prints the error, if any, and exits with the given code.
+func crashOnErrorWithCode(err error, code int) {
 	if err != nil {
-		printer.PrintError(err)
-		os.Exit(1)
+		printer.PrintErrorTo(errorOutput, err)
+		os.Exit(code)
+	}
+}
+
+// redirectOutputToStderr moves the errors and the console logs to stderr so that commands writing
+// data on stdout can be used in shell pipelines.
+func redirectOutputToStderr() {
+	errorOutput = os.Stderr
+	if consoleLogging {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 }
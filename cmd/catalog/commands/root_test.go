@@ -0,0 +1,109 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/cache"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Root command tests", func() {
+
+	var cacheDir string
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-commands")
+		gomega.Expect(err).To(gomega.Succeed())
+		cacheDir = dir
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(cacheDir)
+	})
+
+	// runCommand executes the catalog command in a new process returning its stdout and stderr.
+	runCommand := func(args ...string) (string, string, error) {
+		args = append(args, "--authEnable=false", "--usePlaygroundConfiguration=false", "--cacheDir", cacheDir, "--trashDir", cacheDir)
+		cmd := exec.Command(os.Args[0], "-test.run=TestCommandsPackage")
+		cmd.Env = append(os.Environ(), commandArgsEnv+"="+strings.Join(args, "\n"))
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	ginkgo.It("Should print the errors and logs on stderr when the application is written to stdout", func() {
+		stdout, stderr, err := runCommand("pull", "ns/app:1.0", "--dest", "-", "--offline", "--debug", "--consoleLogging")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(stdout).To(gomega.BeEmpty())
+		gomega.Expect(stderr).To(gomega.ContainSubstring("cannot be pulled in offline mode"))
+		gomega.Expect(stderr).To(gomega.ContainSubstring("Pull received!"))
+	})
+
+	ginkgo.It("Should print the errors on stdout otherwise", func() {
+		stdout, _, err := runCommand("pull", "ns/app:1.0", "--dest", cacheDir, "--offline")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(stdout).To(gomega.ContainSubstring("cannot be pulled in offline mode"))
+	})
+
+	ginkgo.It("Should print the results of a pull in the format chosen with the global --output flag", func() {
+		var buffer bytes.Buffer
+		gw := gzip.NewWriter(&buffer)
+		tw := tar.NewWriter(gw)
+		content := []byte("kind: ApplicationMetadata")
+		gomega.Expect(tw.WriteHeader(&tar.Header{Name: "./metadata.yaml", Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(gomega.Succeed())
+		_, err := tw.Write(content)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(tw.Close()).To(gomega.Succeed())
+		gomega.Expect(gw.Close()).To(gomega.Succeed())
+		archivePath := filepath.Join(cacheDir, "app.tgz")
+		gomega.Expect(os.WriteFile(archivePath, buffer.Bytes(), 0644)).To(gomega.Succeed())
+
+		stdout, _, err := runCommand("push", "ns/app:1.0", archivePath, "--dry-run", "--output", "json")
+		gomega.Expect(err).To(gomega.Succeed())
+		plan := &entities.PushPlan{}
+		gomega.Expect(json.Unmarshal([]byte(stdout), plan)).To(gomega.Succeed())
+		ref := cache.Ref{CatalogURL: plan.CatalogURL, Namespace: "ns", ApplicationName: "app", Tag: "1.0"}
+		gomega.Expect(cache.New(cacheDir).PutBundle(ref, plan.Digest, buffer.Bytes())).To(gomega.Succeed())
+
+		destDir := filepath.Join(cacheDir, "dest")
+		gomega.Expect(os.Mkdir(destDir, 0755)).To(gomega.Succeed())
+		stdout, _, err = runCommand("pull", "ns/app:1.0", "--offline", "--output", "json", "--dest", destDir)
+		gomega.Expect(err).To(gomega.Succeed())
+		result := &entities.PullResult{}
+		gomega.Expect(json.Unmarshal([]byte(stdout), result)).To(gomega.Succeed())
+		gomega.Expect(result.Digest).To(gomega.Equal(plan.Digest))
+		gomega.Expect(result.Path).To(gomega.Equal(filepath.Join(destDir, "app.tgz")))
+		_, err = os.Stat(result.Path)
+		gomega.Expect(err).To(gomega.Succeed())
+
+		_, _, err = runCommand("pull", "ns/app:1.0", "--offline", "--printer", "json")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
@@ -0,0 +1,100 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"time"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/operations"
+	"github.com/spf13/cobra"
+)
+
+// defaultTrashRetention with the default time the entries of the trash are kept.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+var restoreCmdLongHelp = `Push again the application tags saved in a trash entry, with their original visibility.
+
+The application tags are saved in the trash by remove, prune and change-visibility when --backup is
+set. Use catalog trash list to show the available entries. The saved files are pushed exactly as
+they were received from the catalog, without applying the .catalogignore rules, and a tag whose saved
+content does not match its recorded digest is not restored.`
+
+var restoreCmdShortHelp = `Restore the application tags saved in a trash entry.`
+
+var restoreCmd = &cobra.Command{
+	Use:     "restore <trash-entry>",
+	Long:    restoreCmdLongHelp,
+	Short:   restoreCmdShortHelp,
+	Example: "$ catalog restore 20231024-101530",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.Restore(args[0]))
+	},
+}
+
+var trashCmdLongHelp = `Manage the application tags saved before removing them.
+
+The application tags are saved in ~/.napptive/trash, or in the directory set with --trashDir, when
+--backup is set in remove, prune and change-visibility. Each operation creates an entry named after
+its creation time that can be restored with catalog restore. Entries older than --trashRetention are
+removed on each backup.`
+
+var trashCmdShortHelp = `Manage the application tags saved before removing them.`
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Long:  trashCmdLongHelp,
+	Short: trashCmdShortHelp,
+}
+
+var trashListCmdLongHelp = `List the entries of the trash.`
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Long:  trashListCmdLongHelp,
+	Short: trashListCmdLongHelp,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.TrashList())
+	},
+}
+
+var trashPruneCmdLongHelp = `Remove the entries of the trash older than --trashRetention.`
+
+var trashPruneCmd = &cobra.Command{
+	Use:     "prune",
+	Long:    trashPruneCmdLongHelp,
+	Short:   trashPruneCmdLongHelp,
+	Example: "$ catalog trash prune --trashRetention 168h",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := operations.NewCatalog(&cfg)
+		crashOnError(err)
+		crashOnError(catalog.TrashPrune())
+	},
+}
+
+func init() {
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashPruneCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,390 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// refsDir with the directory that contains one entry per application tag.
+	refsDir = "refs"
+	// bundlesDir with the directory that contains the bundles named after their digest.
+	bundlesDir = "bundles"
+	// entrySuffix with the extension of the entry files.
+	entrySuffix = ".json"
+	// bundleSuffix with the extension of the bundle files.
+	bundleSuffix = ".tgz"
+)
+
+// Ref identifies an application tag in a catalog.
+type Ref struct {
+	// CatalogURL with the host:port of the catalog.
+	CatalogURL string
+	// Namespace of the application.
+	Namespace string
+	// ApplicationName with the name of the application.
+	ApplicationName string
+	// Tag of the application.
+	Tag string
+}
+
+// String returns the textual representation of the reference.
+func (r Ref) String() string {
+	return fmt.Sprintf("%s/%s/%s:%s", r.CatalogURL, r.Namespace, r.ApplicationName, r.Tag)
+}
+
+// Cache stores the applications pulled from the catalogs in a local directory. Each application tag
+// has an entry in refs/<catalog>/<namespace>/<appName>/<tag>.json pointing to a bundle stored in
+// bundles/<digest>.tgz, so that tags with the same content share the bundle.
+type Cache struct {
+	// root directory of the cache.
+	root string
+}
+
+// New creates a cache in the given directory.
+func New(root string) *Cache {
+	return &Cache{root: root}
+}
+
+// DefaultDir returns the default location of the cache: ~/.napptive/cache
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nerrors.NewInternalErrorFrom(err, "unable to determine user home")
+	}
+	return filepath.Join(home, ".napptive", "cache"), nil
+}
+
+// Path returns the root directory of the cache.
+func (c *Cache) Path() string {
+	return c.root
+}
+
+// entryPath returns the path of the entry of an application tag.
+func (c *Cache) entryPath(ref Ref) (string, error) {
+	components := []string{strings.ReplaceAll(ref.CatalogURL, ":", "_"), ref.Namespace, ref.ApplicationName, ref.Tag}
+	for _, component := range components {
+		if component == "" || component == "." || component == ".." || strings.ContainsAny(component, `/\`) {
+			return "", nerrors.NewInvalidArgumentError("%s cannot be stored in the cache", ref.String())
+		}
+	}
+	components[len(components)-1] += entrySuffix
+	return filepath.Join(append([]string{c.root, refsDir}, components...)...), nil
+}
+
+// bundlePath returns the path of the bundle with the given digest.
+func (c *Cache) bundlePath(digest string) (string, error) {
+	name := strings.ReplaceAll(digest, ":", "-")
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return "", nerrors.NewInvalidArgumentError("invalid digest %q", digest)
+	}
+	return filepath.Join(c.root, bundlesDir, name+bundleSuffix), nil
+}
+
+// Get returns the entry of an application tag, or nil if it is not cached.
+func (c *Cache) Get(ref Ref) (*entities.CacheEntry, error) {
+	path, err := c.entryPath(ref)
+	if err != nil {
+		return nil, err
+	}
+	return readEntry(path)
+}
+
+// readEntry reads an entry file returning nil if it does not exist.
+func readEntry(path string) (*entities.CacheEntry, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read cache entry %s", path)
+	}
+	entry := &entities.CacheEntry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "invalid cache entry %s", path)
+	}
+	return entry, nil
+}
+
+// ReadBundle returns the content of the bundle of an entry updating its last use.
+func (c *Cache) ReadBundle(ref Ref, entry *entities.CacheEntry) ([]byte, error) {
+	path, err := c.bundlePath(entry.Digest)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nerrors.NewNotFoundError("the bundle of %s is not in the cache", ref.String())
+	}
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read cached bundle of %s", ref.String())
+	}
+	entry.LastUsed = time.Now()
+	if err := c.writeEntry(ref, entry); err != nil {
+		log.Warn().Err(err).Str("ref", ref.String()).Msg("unable to update cache entry")
+	}
+	return content, nil
+}
+
+// PutBundle stores the bundle of an application tag, keeping the cached information of the application.
+func (c *Cache) PutBundle(ref Ref, digest string, content []byte) error {
+	path, err := c.bundlePath(digest)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, content); err != nil {
+		return err
+	}
+	entry, err := c.Get(ref)
+	if err != nil || entry == nil {
+		entry = newEntry(ref)
+	}
+	now := time.Now()
+	entry.Digest = digest
+	entry.PushedDigest = ""
+	entry.Size = int64(len(content))
+	entry.CachedAt = now
+	entry.LastUsed = now
+	return c.writeEntry(ref, entry)
+}
+
+// PutInfo stores the information of an application tag.
+func (c *Cache) PutInfo(ref Ref, info *grpc_catalog_go.InfoApplicationResponse) error {
+	entry, err := c.Get(ref)
+	if err != nil || entry == nil {
+		entry = newEntry(ref)
+	}
+	entry.Info = info
+	entry.LastUsed = time.Now()
+	if entry.CachedAt.IsZero() {
+		entry.CachedAt = entry.LastUsed
+	}
+	return c.writeEntry(ref, entry)
+}
+
+// PutPushedDigest records the digest of the content pushed under an application tag. The cached bundle
+// of the tag, if any, is no longer served if its content is different.
+func (c *Cache) PutPushedDigest(ref Ref, digest string) error {
+	entry, err := c.Get(ref)
+	if err != nil || entry == nil {
+		entry = newEntry(ref)
+	}
+	if entry.Digest != digest {
+		entry.Digest = ""
+		entry.Size = 0
+	}
+	entry.PushedDigest = digest
+	entry.LastUsed = time.Now()
+	if entry.CachedAt.IsZero() {
+		entry.CachedAt = entry.LastUsed
+	}
+	return c.writeEntry(ref, entry)
+}
+
+// newEntry creates an empty entry for an application tag.
+func newEntry(ref Ref) *entities.CacheEntry {
+	return &entities.CacheEntry{
+		CatalogURL:      ref.CatalogURL,
+		Namespace:       ref.Namespace,
+		ApplicationName: ref.ApplicationName,
+		Tag:             ref.Tag,
+	}
+}
+
+// writeEntry stores the entry of an application tag.
+func (c *Cache) writeEntry(ref Ref, entry *entities.CacheEntry) error {
+	path, err := c.entryPath(ref)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to encode cache entry")
+	}
+	return writeFileAtomic(path, content)
+}
+
+// writeFileAtomic writes a file through a temporary file so that concurrent readers never see partial content.
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create cache directory")
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write cache file")
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return nerrors.NewInternalErrorFrom(err, "unable to write cache file")
+	}
+	if err := temp.Close(); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write cache file")
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write cache file")
+	}
+	return nil
+}
+
+// List returns the entries of the cache sorted by application.
+func (c *Cache) List() (*entities.CacheList, error) {
+	result := &entities.CacheList{Path: c.root, Entries: make([]*entities.CacheEntry, 0)}
+	bundles := make(map[string]bool)
+	err := c.walkEntries(func(path string, entry *entities.CacheEntry) error {
+		result.Entries = append(result.Entries, entry)
+		if entry.Digest != "" && !bundles[entry.Digest] {
+			bundles[entry.Digest] = true
+			result.TotalSize += entry.Size
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result.Entries, func(i, j int) bool {
+		return result.Entries[i].ApplicationID() < result.Entries[j].ApplicationID()
+	})
+	return result, nil
+}
+
+// walkEntries calls the function with every entry of the cache.
+func (c *Cache) walkEntries(fn func(path string, entry *entities.CacheEntry) error) error {
+	root := filepath.Join(c.root, refsDir)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, entrySuffix) {
+			return nil
+		}
+		entry, err := readEntry(path)
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("ignoring invalid cache entry")
+			return nil
+		}
+		return fn(path, entry)
+	})
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to read cache %s", c.root)
+	}
+	return nil
+}
+
+// Prune removes the entries not used in the given duration and the bundles that are no longer referenced.
+func (c *Cache) Prune(maxAge time.Duration) (*entities.CacheCleanResult, error) {
+	limit := time.Now().Add(-maxAge)
+	return c.remove(func(entry *entities.CacheEntry) bool {
+		return entry.LastUsed.Before(limit)
+	})
+}
+
+// Clear removes all the content of the cache. Only the directories created by the cache are removed,
+// and nothing is removed if the cache directory contains anything else, as it may have been set to a
+// directory with other files by mistake.
+func (c *Cache) Clear() (*entities.CacheCleanResult, error) {
+	contents, err := os.ReadDir(c.root)
+	if os.IsNotExist(err) {
+		return &entities.CacheCleanResult{Path: c.root}, nil
+	}
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read cache %s", c.root)
+	}
+	var unknown []string
+	for _, content := range contents {
+		if (content.Name() != refsDir && content.Name() != bundlesDir) || !content.IsDir() {
+			unknown = append(unknown, content.Name())
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, nerrors.NewFailedPreconditionError("%s does not look like a cache directory as it contains %s, nothing has been removed", c.root, strings.Join(unknown, ", "))
+	}
+
+	result, err := c.remove(func(entry *entities.CacheEntry) bool {
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{refsDir, bundlesDir} {
+		if err := os.RemoveAll(filepath.Join(c.root, dir)); err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to remove cache %s", c.root)
+		}
+	}
+	return result, nil
+}
+
+// remove deletes the entries selected by the function and the bundles that are no longer referenced.
+func (c *Cache) remove(selected func(entry *entities.CacheEntry) bool) (*entities.CacheCleanResult, error) {
+	result := &entities.CacheCleanResult{Path: c.root}
+	referenced := make(map[string]bool)
+	err := c.walkEntries(func(path string, entry *entities.CacheEntry) error {
+		if selected(entry) {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			result.RemovedEntries++
+			return nil
+		}
+		if entry.Digest != "" {
+			if bundle, err := c.bundlePath(entry.Digest); err == nil {
+				referenced[bundle] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bundles, err := os.ReadDir(filepath.Join(c.root, bundlesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read cache %s", c.root)
+	}
+	for _, bundle := range bundles {
+		path := filepath.Join(c.root, bundlesDir, bundle.Name())
+		if referenced[path] {
+			continue
+		}
+		info, err := bundle.Info()
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to remove %s", path)
+		}
+		result.RemovedBundles++
+		result.FreedBytes += info.Size()
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Cache tests", func() {
+
+	var root string
+	var localCache *Cache
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-cache")
+		gomega.Expect(err).To(gomega.Succeed())
+		root = dir
+		localCache = New(root)
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	ref := Ref{CatalogURL: "catalog.example.com:7060", Namespace: "ns", ApplicationName: "app", Tag: "1.0"}
+
+	ginkgo.It("Should store and read a bundle", func() {
+		entry, err := localCache.Get(ref)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(entry).To(gomega.BeNil())
+
+		gomega.Expect(localCache.PutBundle(ref, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+		entry, err = localCache.Get(ref)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(entry.Digest).To(gomega.Equal("sha256:abc"))
+		gomega.Expect(entry.Size).To(gomega.Equal(int64(7)))
+		content, err := localCache.ReadBundle(ref, entry)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("content"))
+	})
+
+	ginkgo.It("Should keep the information when the bundle is updated", func() {
+		gomega.Expect(localCache.PutInfo(ref, &grpc_catalog_go.InfoApplicationResponse{ApplicationName: "app"})).To(gomega.Succeed())
+		gomega.Expect(localCache.PutBundle(ref, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+		entry, err := localCache.Get(ref)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(entry.Info).NotTo(gomega.BeNil())
+		gomega.Expect(entry.Info.ApplicationName).To(gomega.Equal("app"))
+		gomega.Expect(entry.Digest).To(gomega.Equal("sha256:abc"))
+	})
+
+	ginkgo.It("Should reject references outside the cache", func() {
+		_, err := localCache.Get(Ref{CatalogURL: "catalog", Namespace: "..", ApplicationName: "app", Tag: "1.0"})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(localCache.PutBundle(ref, "sha256:../../evil", []byte("content"))).NotTo(gomega.Succeed())
+	})
+
+	ginkgo.It("Should share the bundles between tags and prune the unused ones", func() {
+		other := ref
+		other.Tag = "latest"
+		gomega.Expect(localCache.PutBundle(ref, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+		gomega.Expect(localCache.PutBundle(other, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+		list, err := localCache.List()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(list.Entries).To(gomega.HaveLen(2))
+		gomega.Expect(list.TotalSize).To(gomega.Equal(int64(7)))
+
+		entry, err := localCache.Get(other)
+		gomega.Expect(err).To(gomega.Succeed())
+		entry.LastUsed = time.Now().Add(-48 * time.Hour)
+		gomega.Expect(localCache.writeEntry(other, entry)).To(gomega.Succeed())
+
+		result, err := localCache.Prune(24 * time.Hour)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(result.RemovedEntries).To(gomega.Equal(1))
+		gomega.Expect(result.RemovedBundles).To(gomega.Equal(0))
+
+		result, err = localCache.Clear()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(result.RemovedEntries).To(gomega.Equal(1))
+		gomega.Expect(result.RemovedBundles).To(gomega.Equal(1))
+		_, err = os.Stat(filepath.Join(root, bundlesDir))
+		gomega.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+		_, err = os.Stat(root)
+		gomega.Expect(err).To(gomega.Succeed())
+	})
+
+	ginkgo.It("Should not clear a directory with other content", func() {
+		gomega.Expect(localCache.PutBundle(ref, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+		other := filepath.Join(root, "notes.txt")
+		gomega.Expect(os.WriteFile(other, []byte("important"), 0644)).To(gomega.Succeed())
+
+		_, err := localCache.Clear()
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("notes.txt"))
+		_, err = os.Stat(other)
+		gomega.Expect(err).To(gomega.Succeed())
+		entry, err := localCache.Get(ref)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(entry).NotTo(gomega.BeNil())
+	})
+
+	ginkgo.It("Should clear a cache that does not exist", func() {
+		result, err := New(filepath.Join(root, "missing")).Clear()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(result.RemovedEntries).To(gomega.Equal(0))
+	})
+})
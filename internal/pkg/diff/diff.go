@@ -0,0 +1,265 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultContext with the number of unchanged lines shown around each change.
+	DefaultContext = 3
+	// maxTableSize with the maximum number of cells of the LCS table. Bigger changes are shown as
+	// the removal of all the lines followed by the addition of the new ones.
+	maxTableSize = 16 * 1024 * 1024
+	// binaryCheckSize with the number of bytes inspected to detect binary content.
+	binaryCheckSize = 8000
+	// noNewline with the marker added after a line without a final newline.
+	noNewline = "\\ No newline at end of file\n"
+)
+
+// operation applied to a line.
+type operation byte
+
+const (
+	opEqual  operation = ' '
+	opDelete operation = '-'
+	opInsert operation = '+'
+)
+
+// edit with a line of the diff.
+type edit struct {
+	op   operation
+	line string
+}
+
+// Patch with the unified diff between two texts.
+type Patch struct {
+	// Text with the unified diff, empty if both texts are equal.
+	Text string
+	// Additions with the number of lines added.
+	Additions int
+	// Deletions with the number of lines removed.
+	Deletions int
+}
+
+// IsBinary checks if the content is not text, looking for NUL bytes or invalid UTF-8 sequences.
+func IsBinary(content []byte) bool {
+	sample := content
+	if len(sample) > binaryCheckSize {
+		sample = sample[:binaryCheckSize]
+		// do not cut a multi-byte character
+		for i := 0; i < utf8.UTFMax && len(sample) > 0 && !utf8.Valid(sample); i++ {
+			sample = sample[:len(sample)-1]
+		}
+	}
+	return bytes.IndexByte(sample, 0) != -1 || !utf8.Valid(sample)
+}
+
+// Unified returns the unified diff between two texts, using /dev/null as name for missing files.
+func Unified(fromName string, toName string, from []byte, to []byte, context int) *Patch {
+	if context < 0 {
+		context = DefaultContext
+	}
+	edits := lineEdits(splitLines(from), splitLines(to))
+	result := &Patch{}
+	for _, e := range edits {
+		switch e.op {
+		case opInsert:
+			result.Additions++
+		case opDelete:
+			result.Deletions++
+		}
+	}
+	if result.Additions == 0 && result.Deletions == 0 {
+		return result
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+	writeHunks(&builder, edits, context)
+	result.Text = builder.String()
+	return result
+}
+
+// splitLines splits a text keeping the line terminators so that a missing final newline is detected.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineEdits returns the edits that transform a into b. The common prefix and suffix are removed
+// before computing the longest common subsequence of the remaining lines.
+func lineEdits(a []string, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		result = append(result, edit{op: opEqual, line: line})
+	}
+	result = append(result, lcsEdits(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, edit{op: opEqual, line: line})
+	}
+	return result
+}
+
+// lcsEdits computes the edits between two lists of lines using the longest common subsequence.
+func lcsEdits(a []string, b []string) []edit {
+	n, m := len(a), len(b)
+	result := make([]edit, 0, n+m)
+	if n*m > maxTableSize {
+		for _, line := range a {
+			result = append(result, edit{op: opDelete, line: line})
+		}
+		for _, line := range b {
+			result = append(result, edit{op: opInsert, line: line})
+		}
+		return result
+	}
+
+	// table[i*width+j] with the length of the LCS of a[i:] and b[j:]
+	width := m + 1
+	table := make([]int32, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i*width+j] = table[(i+1)*width+j+1] + 1
+			} else if table[(i+1)*width+j] >= table[i*width+j+1] {
+				table[i*width+j] = table[(i+1)*width+j]
+			} else {
+				table[i*width+j] = table[i*width+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, edit{op: opEqual, line: a[i]})
+			i++
+			j++
+		case table[(i+1)*width+j] >= table[i*width+j+1]:
+			result = append(result, edit{op: opDelete, line: a[i]})
+			i++
+		default:
+			result = append(result, edit{op: opInsert, line: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, edit{op: opDelete, line: a[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, edit{op: opInsert, line: b[j]})
+	}
+	return result
+}
+
+// writeHunks writes the changes grouped in hunks with the given number of context lines.
+func writeHunks(builder *strings.Builder, edits []edit, context int) {
+	// fromLine and toLine with the number of lines of each text before every edit
+	fromLine := make([]int, len(edits)+1)
+	toLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if e.op != opInsert {
+			fromLine[i+1]++
+		}
+		if e.op != opDelete {
+			toLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == opEqual {
+			i++
+			continue
+		}
+		start := max(0, i-context)
+		end := hunkEnd(edits, i, context)
+		fromStart, fromLength := fromLine[start], fromLine[end]-fromLine[start]
+		toStart, toLength := toLine[start], toLine[end]-toLine[start]
+		fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(fromStart, fromLength), hunkRange(toStart, toLength))
+		for _, e := range edits[start:end] {
+			builder.WriteByte(byte(e.op))
+			builder.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				builder.WriteString("\n" + noNewline)
+			}
+		}
+		i = end
+	}
+}
+
+// hunkEnd returns the end of the hunk that starts with the change at index first, merging the
+// changes separated by less than twice the number of context lines.
+func hunkEnd(edits []edit, first int, context int) int {
+	last := first
+	for i := first + 1; i < len(edits); i++ {
+		if edits[i].op == opEqual {
+			continue
+		}
+		if i-last-1 > 2*context {
+			break
+		}
+		last = i
+	}
+	return min(len(edits), last+context+1)
+}
+
+// hunkRange returns the range of lines of a hunk as start,length. Empty ranges refer to the line
+// before the change.
+func hunkRange(start int, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
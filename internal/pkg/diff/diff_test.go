@@ -0,0 +1,63 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Diff tests", func() {
+
+	ginkgo.It("Should return an empty patch for equal texts", func() {
+		patch := Unified("a/f", "b/f", []byte("a\nb\n"), []byte("a\nb\n"), DefaultContext)
+		gomega.Expect(patch.Text).To(gomega.BeEmpty())
+		gomega.Expect(patch.Additions).To(gomega.Equal(0))
+		gomega.Expect(patch.Deletions).To(gomega.Equal(0))
+	})
+
+	ginkgo.It("Should produce a unified diff with context", func() {
+		from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+		to := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+		patch := Unified("a/f", "b/f", []byte(from), []byte(to), 2)
+		gomega.Expect(patch.Text).To(gomega.Equal("--- a/f\n+++ b/f\n@@ -3,5 +3,5 @@\n 3\n 4\n-5\n+five\n 6\n 7\n"))
+		gomega.Expect(patch.Additions).To(gomega.Equal(1))
+		gomega.Expect(patch.Deletions).To(gomega.Equal(1))
+	})
+
+	ginkgo.It("Should split distant changes in several hunks", func() {
+		from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+		to := "one\n2\n3\n4\n5\n6\n7\n8\nnine\n"
+		patch := Unified("a/f", "b/f", []byte(from), []byte(to), 1)
+		gomega.Expect(patch.Text).To(gomega.Equal("--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -8,2 +8,2 @@\n 8\n-9\n+nine\n"))
+	})
+
+	ginkgo.It("Should handle new files and missing final newlines", func() {
+		patch := Unified("/dev/null", "b/f", nil, []byte("a\nb"), DefaultContext)
+		gomega.Expect(patch.Text).To(gomega.Equal("--- /dev/null\n+++ b/f\n@@ -0,0 +1,2 @@\n+a\n+b\n\\ No newline at end of file\n"))
+		gomega.Expect(patch.Additions).To(gomega.Equal(2))
+
+		patch = Unified("a/f", "b/f", []byte("a\nb"), []byte("a\nb\n"), DefaultContext)
+		gomega.Expect(patch.Text).To(gomega.Equal("--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"))
+	})
+
+	ginkgo.It("Should detect binary content", func() {
+		gomega.Expect(IsBinary([]byte("kind: Application\n"))).To(gomega.BeFalse())
+		gomega.Expect(IsBinary([]byte{0x1f, 0x8b, 0x00, 0x01})).To(gomega.BeTrue())
+		gomega.Expect(IsBinary([]byte{0xff, 0xfe, 'a'})).To(gomega.BeTrue())
+	})
+})
@@ -0,0 +1,275 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// escapable with the characters that can be escaped with a backslash.
+const escapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// htmlTagRegex matches the HTML tags embedded in the text, which are removed.
+var htmlTagRegex = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(\s[^<>]*)?/?>`)
+
+// autolinkRegex matches the links written between angle brackets.
+var autolinkRegex = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]*:[^<>\s]*)>`)
+
+// inlineRenderer renders the inline elements of a block: emphasis, code spans, links and images.
+type inlineRenderer struct {
+	// references with the targets of the reference links defined in the document.
+	references map[string]string
+}
+
+// render returns the styled text.
+func (r *inlineRenderer) render(text string) string {
+	var out strings.Builder
+	var literal strings.Builder
+	flush := func() {
+		out.WriteString(html.UnescapeString(literal.String()))
+		literal.Reset()
+	}
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+			literal.WriteByte(text[i+1])
+			i += 2
+			continue
+		case c == '`':
+			content, size, ok := codeSpan(rest)
+			if !ok {
+				// An unmatched run of backticks is kept as text
+				size = backtickRun(rest)
+				literal.WriteString(rest[:size])
+				i += size
+				continue
+			}
+			flush()
+			out.WriteString(styleCode + content + styleColorOff)
+			i += size
+			continue
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if label, _, size, ok := r.link(rest[1:]); ok {
+				flush()
+				out.WriteString(r.render(label))
+				i += size + 1
+				continue
+			}
+		case c == '[':
+			if label, target, size, ok := r.link(rest); ok {
+				flush()
+				out.WriteString(r.renderLink(label, target))
+				i += size
+				continue
+			}
+		case c == '<':
+			if match := autolinkRegex.FindStringSubmatch(rest); match != nil {
+				flush()
+				out.WriteString(styleUnderline + match[1] + styleUnderOff)
+				i += len(match[0])
+				continue
+			}
+			if match := htmlTagRegex.FindString(rest); match != "" {
+				i += len(match)
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			if styled, size, ok := r.emphasis(text, i); ok {
+				flush()
+				out.WriteString(styled)
+				i += size
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(rest)
+		literal.WriteString(rest[:size])
+		i += size
+	}
+	flush()
+	return out.String()
+}
+
+// renderLink returns the label of a link followed by its target, unless both are the same.
+func (r *inlineRenderer) renderLink(label string, target string) string {
+	rendered := styleUnderline + r.render(label) + styleUnderOff
+	if target == "" || target == label || strings.HasPrefix(target, "#") {
+		return rendered
+	}
+	return rendered + styleDim + " (" + target + ")" + styleBoldOff
+}
+
+// codeSpan parses a code span starting at the beginning of the text, returning its content and its size.
+// The span is closed by a run of backticks with the same length as the opening one.
+func codeSpan(text string) (string, int, bool) {
+	ticks := backtickRun(text)
+	for end := ticks; end < len(text); {
+		if text[end] != '`' {
+			end++
+			continue
+		}
+		run := backtickRun(text[end:])
+		if run == ticks {
+			content := text[ticks:end]
+			if len(content) > 1 && strings.HasPrefix(content, " ") && strings.HasSuffix(content, " ") && strings.TrimSpace(content) != "" {
+				content = content[1 : len(content)-1]
+			}
+			return content, end + run, true
+		}
+		end += run
+	}
+	return "", 0, false
+}
+
+// backtickRun returns the number of backticks at the start of the text.
+func backtickRun(text string) int {
+	return len(text) - len(strings.TrimLeft(text, "`"))
+}
+
+// link parses a link starting with its label, [label](target) or [label][reference], returning the
+// label, the target and the size of the link.
+func (r *inlineRenderer) link(text string) (string, string, int, bool) {
+	end := closingBracket(text)
+	if end < 0 {
+		return "", "", 0, false
+	}
+	label := text[1:end]
+	rest := text[end+1:]
+	if strings.HasPrefix(rest, "(") {
+		closing := closingParenthesis(rest)
+		if closing < 0 {
+			return "", "", 0, false
+		}
+		target := strings.TrimSpace(rest[1:closing])
+		// Remove the optional title
+		if index := strings.IndexAny(target, " \t"); index >= 0 {
+			target = target[:index]
+		}
+		target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+		return label, target, end + 1 + closing + 1, true
+	}
+	if strings.HasPrefix(rest, "[") {
+		if closing := strings.Index(rest, "]"); closing >= 0 {
+			name := rest[1:closing]
+			if name == "" {
+				name = label
+			}
+			if target, exists := r.references[normalizeReference(name)]; exists {
+				return label, target, end + 1 + closing + 1, true
+			}
+		}
+	}
+	if target, exists := r.references[normalizeReference(label)]; exists {
+		return label, target, end + 1, true
+	}
+	return "", "", 0, false
+}
+
+// closingBracket returns the position of the bracket that closes the one at the start of the text.
+func closingBracket(text string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// closingParenthesis returns the position of the parenthesis that closes the one at the start of the text.
+func closingParenthesis(text string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// emphasis parses an emphasis starting at the given position: **bold**, *italic* or ~~strikethrough~~.
+// Underscores only delimit emphasis at word boundaries so that identifiers like snake_case are kept.
+func (r *inlineRenderer) emphasis(text string, start int) (string, int, bool) {
+	c := text[start]
+	run := 1
+	for start+run < len(text) && text[start+run] == c {
+		run++
+	}
+	if run > 2 || (c == '~' && run != 2) {
+		return "", 0, false
+	}
+	delimiter := text[start : start+run]
+	contentStart := start + run
+	if contentStart >= len(text) || text[contentStart] == ' ' {
+		return "", 0, false
+	}
+	if c == '_' && start > 0 && isWordChar(text[start-1]) {
+		return "", 0, false
+	}
+	for end := contentStart + 1; end <= len(text)-run; end++ {
+		if text[end:end+run] != delimiter || text[end-1] == ' ' || text[end-1] == '\\' {
+			continue
+		}
+		// The closing delimiter must not be part of a longer run
+		if (end+run < len(text) && text[end+run] == c) || text[end-1] == c {
+			continue
+		}
+		if c == '_' && end+run < len(text) && isWordChar(text[end+run]) {
+			continue
+		}
+		inner := r.render(text[contentStart:end])
+		switch {
+		case c == '~':
+			return styleStrike + inner + styleStrikeOff, end + run - start, true
+		case run == 2:
+			return styleBold + inner + styleBoldOff, end + run - start, true
+		default:
+			return styleItalic + inner + styleItalicOff, end + run - start, true
+		}
+	}
+	return "", 0, false
+}
+
+// isWordChar checks if a byte is part of a word.
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// normalizeReference returns the key of a link reference.
+func normalizeReference(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
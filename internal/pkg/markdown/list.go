@@ -0,0 +1,150 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"strings"
+)
+
+// listItem with the lines of an item of a list, relative to the indentation of its content.
+type listItem struct {
+	// marker shown before the content.
+	marker string
+	// lines with the content of the item.
+	lines []string
+	// loose is set if the item contains blank lines between its blocks.
+	loose bool
+}
+
+// isOrdered checks if a list marker belongs to an ordered list.
+func isOrdered(marker string) bool {
+	return strings.HasSuffix(marker, ".") || strings.HasSuffix(marker, ")")
+}
+
+// list renders a list with its nested content. Items of unordered lists are shown with a bullet and
+// items of ordered lists keep their number.
+func (r *renderer) list(lines []string, start int, width int) ([]string, int) {
+	first := listItemRegex.FindStringSubmatch(lines[start])
+	ordered := isOrdered(first[2])
+	items := make([]*listItem, 0)
+	loose := false
+	i := start
+	for i < len(lines) {
+		match := listItemRegex.FindStringSubmatch(lines[i])
+		if match == nil || isOrdered(match[2]) != ordered {
+			break
+		}
+		item, next := parseListItem(lines, i, match)
+		items = append(items, item)
+		loose = loose || item.loose
+		i = next
+		// Items separated by blank lines make the list loose
+		blank := i
+		for blank < len(lines) && strings.TrimSpace(lines[blank]) == "" {
+			blank++
+		}
+		if blank == len(lines) {
+			break
+		}
+		if sibling := listItemRegex.FindStringSubmatch(lines[blank]); sibling == nil || isOrdered(sibling[2]) != ordered {
+			break
+		}
+		if blank > i {
+			loose = true
+		}
+		i = blank
+	}
+
+	markerWidth := 0
+	for _, item := range items {
+		if len([]rune(item.marker)) > markerWidth {
+			markerWidth = len([]rune(item.marker))
+		}
+	}
+	markerWidth++
+	result := make([]string, 0)
+	for index, item := range items {
+		if index > 0 && loose {
+			result = append(result, "")
+		}
+		content := r.blocks(item.lines, width-markerWidth, !loose)
+		if len(content) == 0 {
+			content = []string{""}
+		}
+		for lineIndex, line := range content {
+			prefix := strings.Repeat(" ", markerWidth)
+			if lineIndex == 0 {
+				prefix = padRight(item.marker, markerWidth)
+			}
+			result = append(result, strings.TrimRight(prefix+line, " "))
+		}
+	}
+	return result, i
+}
+
+// parseListItem reads the lines of the list item starting at the given line.
+func parseListItem(lines []string, start int, match []string) (*listItem, int) {
+	indent, marker, spacing, content := len(match[1]), match[2], match[3], match[4]
+	if content == "" {
+		// The content starts on the next line
+		spacing = " "
+	} else if len(spacing) > 4 {
+		// The content is an indented code block
+		content = strings.Repeat(" ", len(spacing)-1) + content
+		spacing = " "
+	}
+	contentIndent := indent + len(marker) + len(spacing)
+	item := &listItem{marker: "•", lines: []string{content}}
+	if isOrdered(marker) {
+		item.marker = marker
+	}
+	if task := taskRegex.FindStringSubmatch(content); task != nil {
+		item.marker = "☐"
+		if task[1] != " " {
+			item.marker = "☑"
+		}
+		item.lines[0] = content[len(task[0]):]
+	}
+
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			// Blank lines belong to the item if it continues after them
+			next := i
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next == len(lines) || indentation(lines[next]) < contentIndent {
+				break
+			}
+			item.lines = append(item.lines, "")
+			item.loose = true
+			continue
+		}
+		if indentation(line) >= contentIndent {
+			item.lines = append(item.lines, line[contentIndent:])
+			continue
+		}
+		// Lazy continuation of the paragraph of the item
+		if startsBlock(line) || listItemRegex.MatchString(line) || strings.TrimSpace(item.lines[len(item.lines)-1]) == "" {
+			break
+		}
+		item.lines = append(item.lines, strings.TrimSpace(line))
+	}
+	return item, i
+}
@@ -0,0 +1,308 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package markdown renders markdown documents for the terminal.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// minWidth with the minimum width used to wrap the text.
+	minWidth = 20
+	// codeIndent with the indentation of the code blocks.
+	codeIndent = "    "
+	// tabSpaces with the spaces that replace each tab so that the output can be aligned.
+	tabSpaces = "    "
+)
+
+var (
+	headingRegex   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	setextH1Regex  = regexp.MustCompile(`^ {0,3}=+\s*$`)
+	setextH2Regex  = regexp.MustCompile(`^ {0,3}-+\s*$`)
+	fenceRegex     = regexp.MustCompile("^( {0,3})(```+|~~~+)")
+	listItemRegex  = regexp.MustCompile(`^( *)([-*+]|\d{1,9}[.)])( +|$)(.*)$`)
+	quoteRegex     = regexp.MustCompile(`^ {0,3}> ?`)
+	tableSepRegex  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	referenceRegex = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:\s*<?([^\s>]+)>?(\s+.*)?$`)
+	taskRegex      = regexp.MustCompile(`^\[([ xX])\]\s+`)
+)
+
+// renderer renders the blocks of a markdown document.
+type renderer struct {
+	inline *inlineRenderer
+}
+
+// Render returns the markdown document styled for a terminal with the given width. Headings, lists,
+// code blocks, block quotes, tables, emphasis and links are supported; HTML tags are removed.
+func Render(source []byte, width int) string {
+	if width < minWidth {
+		width = minWidth
+	}
+	text := strings.ReplaceAll(string(source), "\r\n", "\n")
+	text = stripControl(text)
+	text = strings.ReplaceAll(text, "\t", tabSpaces)
+	r := &renderer{inline: &inlineRenderer{references: make(map[string]string)}}
+	lines := r.collectReferences(strings.Split(text, "\n"))
+	return strings.Join(r.blocks(lines, width, false), "\n") + "\n"
+}
+
+// stripControl removes the C0 and C1 control characters except new lines and tabs, so that the document
+// cannot inject escape sequences in the terminal or interfere with the styles of the renderer. Invalid
+// UTF-8 bytes are replaced as they could be interpreted as C1 characters.
+func stripControl(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// collectReferences stores the targets of the link reference definitions, returning the rest of the lines.
+func (r *renderer) collectReferences(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	fence := ""
+	for _, line := range lines {
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			if fence == "" {
+				fence = match[2]
+			} else if isClosingFence(line, fence) {
+				fence = ""
+			}
+		}
+		if fence == "" {
+			if match := referenceRegex.FindStringSubmatch(line); match != nil {
+				r.inline.references[normalizeReference(match[1])] = match[2]
+				continue
+			}
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// blocks renders a sequence of lines. Blocks are separated by an empty line unless tight is set.
+func (r *renderer) blocks(lines []string, width int, tight bool) []string {
+	result := make([]string, 0)
+	add := func(block []string) {
+		if len(block) == 0 {
+			return
+		}
+		if len(result) > 0 && !tight {
+			result = append(result, "")
+		}
+		result = append(result, block...)
+	}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		var block []string
+		switch {
+		case trimmed == "":
+			i++
+			continue
+		case strings.HasPrefix(trimmed, "<!--"):
+			for i < len(lines) && !strings.Contains(lines[i], "-->") {
+				i++
+			}
+			i++
+			continue
+		case fenceRegex.MatchString(line):
+			block, i = r.fencedCode(lines, i)
+		case indentation(line) >= len(codeIndent):
+			block, i = r.indentedCode(lines, i)
+		case headingRegex.MatchString(line):
+			match := headingRegex.FindStringSubmatch(line)
+			block = r.heading(len(match[1]), match[2], width)
+			i++
+		case isRule(line):
+			block = []string{styleDim + strings.Repeat("─", width) + styleBoldOff}
+			i++
+		case quoteRegex.MatchString(line):
+			block, i = r.quote(lines, i, width)
+		case listItemRegex.MatchString(line):
+			block, i = r.list(lines, i, width)
+		case isTableStart(lines, i):
+			block, i = r.table(lines, i)
+		default:
+			block, i = r.paragraph(lines, i, width)
+		}
+		add(block)
+	}
+	return result
+}
+
+// indentation returns the number of spaces at the beginning of a line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isRule checks if a line is a thematic break: three or more -, * or _ optionally separated by spaces.
+func isRule(line string) bool {
+	if indentation(line) > 3 {
+		return false
+	}
+	compact := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	if len(compact) < 3 || !strings.Contains("-*_", compact[:1]) {
+		return false
+	}
+	return strings.Trim(compact, compact[:1]) == ""
+}
+
+// isClosingFence checks if a line closes a code block opened with the given fence.
+func isClosingFence(line string, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return indentation(line) <= 3 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// startsBlock checks if a line interrupts a paragraph.
+func startsBlock(line string) bool {
+	return fenceRegex.MatchString(line) || headingRegex.MatchString(line) || isRule(line) ||
+		quoteRegex.MatchString(line) || strings.HasPrefix(strings.TrimSpace(line), "<!--") ||
+		(listItemRegex.MatchString(line) && strings.TrimSpace(listItemRegex.FindStringSubmatch(line)[4]) != "")
+}
+
+// codeLines styles the lines of a code block, which are not wrapped.
+func codeLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			result = append(result, "")
+			continue
+		}
+		result = append(result, codeIndent+styleCode+line+styleColorOff)
+	}
+	return result
+}
+
+// fencedCode renders a code block delimited by ``` or ~~~.
+func (r *renderer) fencedCode(lines []string, start int) ([]string, int) {
+	match := fenceRegex.FindStringSubmatch(lines[start])
+	indent, fence := len(match[1]), match[2]
+	content := make([]string, 0)
+	i := start + 1
+	for ; i < len(lines) && !isClosingFence(lines[i], fence); i++ {
+		line := lines[i]
+		remove := indentation(line)
+		if remove > indent {
+			remove = indent
+		}
+		content = append(content, line[remove:])
+	}
+	return codeLines(content), i + 1
+}
+
+// indentedCode renders a code block indented with four spaces.
+func (r *renderer) indentedCode(lines []string, start int) ([]string, int) {
+	content := make([]string, 0)
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			content = append(content, "")
+			continue
+		}
+		if indentation(line) < len(codeIndent) {
+			break
+		}
+		content = append(content, line[len(codeIndent):])
+	}
+	return codeLines(content), i
+}
+
+// heading renders a heading, underlining the first level ones.
+func (r *renderer) heading(level int, text string, width int) []string {
+	style := styleBold
+	if level == 1 {
+		style += styleUnderline
+	}
+	lines := wrap(r.inline.render(text), width)
+	for i, line := range lines {
+		lines[i] = style + line + styleReset
+	}
+	return lines
+}
+
+// quote renders a block quote with a bar before its content.
+func (r *renderer) quote(lines []string, start int, width int) ([]string, int) {
+	content := make([]string, 0)
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if quoteRegex.MatchString(line) {
+			content = append(content, quoteRegex.ReplaceAllString(line, ""))
+			continue
+		}
+		// Lazy continuation of a paragraph of the quote
+		if strings.TrimSpace(line) == "" || startsBlock(line) || len(content) == 0 || strings.TrimSpace(content[len(content)-1]) == "" {
+			break
+		}
+		content = append(content, line)
+	}
+	result := r.blocks(content, width-2, false)
+	for index, line := range result {
+		result[index] = strings.TrimRight(styleDim+"│"+styleBoldOff+" "+line, " ")
+	}
+	return result, i
+}
+
+// paragraph renders a paragraph, or a heading underlined with = or -, wrapping its lines.
+func (r *renderer) paragraph(lines []string, start int, width int) ([]string, int) {
+	segments := make([]string, 0)
+	var current []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || (i > start && startsBlock(line) && !setextH2Regex.MatchString(line)) {
+			break
+		}
+		if i > start && setextH1Regex.MatchString(line) {
+			return r.heading(1, strings.Join(append(segments, current...), " "), width), i + 1
+		}
+		if i > start && setextH2Regex.MatchString(line) {
+			return r.heading(2, strings.Join(append(segments, current...), " "), width), i + 1
+		}
+		// Two trailing spaces or a backslash force a line break
+		if strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\") {
+			current = append(current, strings.TrimSuffix(strings.TrimSpace(line), "\\"))
+			segments = append(segments, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, strings.TrimSpace(line))
+	}
+	if len(current) > 0 {
+		segments = append(segments, strings.Join(current, " "))
+	}
+	result := make([]string, 0)
+	for _, segment := range segments {
+		rendered := r.inline.render(segment)
+		if strings.TrimSpace(sgrRegex.ReplaceAllString(rendered, "")) == "" {
+			continue
+		}
+		result = append(result, wrap(rendered, width)...)
+	}
+	return result, i
+}
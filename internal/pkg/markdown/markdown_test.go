@@ -0,0 +1,102 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"strings"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+// plain returns the rendered document without styles.
+func plain(source string, width int) string {
+	return sgrRegex.ReplaceAllString(Render([]byte(source), width), "")
+}
+
+var _ = ginkgo.Describe("Markdown tests", func() {
+
+	ginkgo.It("Should style headings and emphasis", func() {
+		rendered := Render([]byte("# Title\n\nSome **bold**, *italic*, ~~old~~ and `code` text."), 80)
+		gomega.Expect(rendered).To(gomega.HavePrefix(styleBold + styleUnderline + "Title" + styleReset))
+		gomega.Expect(rendered).To(gomega.ContainSubstring(styleBold + "bold" + styleBoldOff))
+		gomega.Expect(rendered).To(gomega.ContainSubstring(styleItalic + "italic" + styleItalicOff))
+		gomega.Expect(rendered).To(gomega.ContainSubstring(styleStrike + "old" + styleStrikeOff))
+		gomega.Expect(rendered).To(gomega.ContainSubstring(styleCode + "code" + styleColorOff))
+		gomega.Expect(plain("Setext\n======\n", 80)).To(gomega.Equal("Setext\n"))
+	})
+
+	ginkgo.It("Should keep underscores inside words and escaped characters", func() {
+		gomega.Expect(plain("use snake_case_names and \\*stars\\* &amp; more", 80)).To(gomega.Equal("use snake_case_names and *stars* & more\n"))
+		gomega.Expect(plain("unmatched `` backticks", 80)).To(gomega.Equal("unmatched `` backticks\n"))
+	})
+
+	ginkgo.It("Should wrap paragraphs to the width", func() {
+		rendered := plain("one two three four five six seven eight nine ten eleven twelve thirteen", 20)
+		for _, line := range strings.Split(strings.TrimSuffix(rendered, "\n"), "\n") {
+			gomega.Expect(len(line)).To(gomega.BeNumerically("<=", 20))
+		}
+		gomega.Expect(strings.Fields(rendered)).To(gomega.HaveLen(13))
+		gomega.Expect(plain("first  \nsecond", 80)).To(gomega.Equal("first\nsecond\n"))
+	})
+
+	ginkgo.It("Should close the styles at the end of the wrapped lines", func() {
+		lines := wrap(styleBold+"one two three four"+styleBoldOff+" five", 10)
+		gomega.Expect(lines).To(gomega.HaveLen(3))
+		gomega.Expect(lines[0]).To(gomega.HaveSuffix(styleReset))
+		gomega.Expect(lines[1]).To(gomega.HavePrefix(styleBold))
+	})
+
+	ginkgo.It("Should render links with their target", func() {
+		gomega.Expect(plain("See [the docs](https://docs.example.com \"Docs\").", 80)).To(gomega.Equal("See the docs (https://docs.example.com).\n"))
+		gomega.Expect(plain("See [docs][ref] and <https://example.com>.\n\n[ref]: https://ref.example.com", 80)).To(gomega.Equal("See docs (https://ref.example.com) and https://example.com.\n"))
+		gomega.Expect(plain("[![badge](https://badge.svg)](https://ci.example.com) [top](#top)", 80)).To(gomega.Equal("badge (https://ci.example.com) top\n"))
+	})
+
+	ginkgo.It("Should render nested lists", func() {
+		source := "- one\n- two\n  - nested\n- [x] done\n\n1. first\n2. second\n"
+		gomega.Expect(plain(source, 80)).To(gomega.Equal("• one\n• two\n  • nested\n☑ done\n\n1. first\n2. second\n"))
+		gomega.Expect(plain("1. first\n\n   text\n2. second\n", 80)).To(gomega.Equal("1. first\n\n   text\n\n2. second\n"))
+	})
+
+	ginkgo.It("Should keep code blocks without wrapping them", func() {
+		source := "```yaml\nkind: Application\n\tname: a very long line that is not wrapped\n```\n\n    indented\n"
+		gomega.Expect(plain(source, 20)).To(gomega.Equal("    kind: Application\n        name: a very long line that is not wrapped\n\n    indented\n"))
+	})
+
+	ginkgo.It("Should render block quotes, rules and tables", func() {
+		gomega.Expect(plain("> quoted\n> text", 80)).To(gomega.Equal("│ quoted text\n"))
+		gomega.Expect(plain("---", 20)).To(gomega.Equal(strings.Repeat("─", 20) + "\n"))
+		source := "| Name | Size |\n|------|-----:|\n| app | 1 |\n| other \\| app | 200 |\n"
+		gomega.Expect(plain(source, 80)).To(gomega.Equal("Name        │ Size\n────────────┼─────\napp         │    1\nother | app │  200\n"))
+	})
+
+	ginkgo.It("Should remove HTML tags and comments", func() {
+		gomega.Expect(plain("<p align=\"center\"><img src=\"logo.png\"></p>\n\n<!-- hidden\ncomment -->\nText<br/>", 80)).To(gomega.Equal("Text\n"))
+	})
+	ginkgo.It("Should remove control characters from the document", func() {
+		rendered := Render([]byte("Title\x1b]0;owned\x07 **bold\x1b[0m** text\x9b31m\r\n\n`code\x08`\tend\xc2\x9b"), 80)
+		gomega.Expect(rendered).NotTo(gomega.ContainSubstring("\x07"))
+		gomega.Expect(rendered).NotTo(gomega.ContainSubstring("\x08"))
+		gomega.Expect(rendered).NotTo(gomega.ContainSubstring("\r"))
+		gomega.Expect(rendered).NotTo(gomega.ContainSubstring("\u009b"))
+		gomega.Expect(rendered).NotTo(gomega.ContainSubstring("\x1b]"))
+		gomega.Expect(strings.Count(rendered, "\x1b")).To(gomega.Equal(strings.Count(rendered, "\x1b[")))
+		gomega.Expect(rendered).To(gomega.ContainSubstring(styleBold + "bold[0m" + styleBoldOff))
+		gomega.Expect(plain("a\x1b[31mred\x00 text", 80)).To(gomega.Equal("a[31mred text\n"))
+	})
+})
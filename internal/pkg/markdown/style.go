@@ -0,0 +1,130 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used to style the rendered text.
+const (
+	styleReset     = "\x1b[0m"
+	styleBold      = "\x1b[1m"
+	styleDim       = "\x1b[2m"
+	styleItalic    = "\x1b[3m"
+	styleUnderline = "\x1b[4m"
+	styleStrike    = "\x1b[9m"
+	styleCode      = "\x1b[36m"
+	styleBoldOff   = "\x1b[22m"
+	styleItalicOff = "\x1b[23m"
+	styleUnderOff  = "\x1b[24m"
+	styleStrikeOff = "\x1b[29m"
+	styleColorOff  = "\x1b[39m"
+)
+
+// sgrRegex matches the ANSI sequences that set the style of the text.
+var sgrRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// visibleLen returns the number of characters of a text that are shown in the terminal.
+func visibleLen(text string) int {
+	return utf8.RuneCountInString(sgrRegex.ReplaceAllString(text, ""))
+}
+
+// padRight fills a text with spaces up to the given visible width.
+func padRight(text string, width int) string {
+	if missing := width - visibleLen(text); missing > 0 {
+		return text + strings.Repeat(" ", missing)
+	}
+	return text
+}
+
+// activeStyles keeps the styles that are applied at a point of the text so that they can be closed at
+// the end of a line and opened again on the next one, leaving the prefixes of the lines unstyled.
+type activeStyles []string
+
+// update applies the sequences found in a text.
+func (a activeStyles) update(text string) activeStyles {
+	for _, code := range sgrRegex.FindAllString(text, -1) {
+		switch code {
+		case styleReset:
+			a = a[:0]
+		case styleBoldOff:
+			a = a.without(styleBold, styleDim)
+		case styleItalicOff:
+			a = a.without(styleItalic)
+		case styleUnderOff:
+			a = a.without(styleUnderline)
+		case styleStrikeOff:
+			a = a.without(styleStrike)
+		case styleColorOff:
+			a = a.without(styleCode)
+		default:
+			a = append(a, code)
+		}
+	}
+	return a
+}
+
+// without removes the given styles.
+func (a activeStyles) without(codes ...string) activeStyles {
+	result := a[:0]
+	for _, active := range a {
+		removed := false
+		for _, code := range codes {
+			removed = removed || active == code
+		}
+		if !removed {
+			result = append(result, active)
+		}
+	}
+	return result
+}
+
+// wrap splits a styled text in lines of at most the given visible width. Words longer than the
+// width are placed in their own line without splitting them.
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	result := make([]string, 0)
+	var line strings.Builder
+	lineLen := 0
+	var active activeStyles
+	for _, word := range words {
+		wordLen := visibleLen(word)
+		if lineLen > 0 && lineLen+1+wordLen > width {
+			if len(active) > 0 {
+				line.WriteString(styleReset)
+			}
+			result = append(result, line.String())
+			line.Reset()
+			line.WriteString(strings.Join(active, ""))
+			lineLen = 0
+		}
+		if lineLen > 0 {
+			line.WriteString(" ")
+			lineLen++
+		}
+		line.WriteString(word)
+		lineLen += wordLen
+		active = active.update(word)
+	}
+	return append(result, line.String())
+}
@@ -0,0 +1,131 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package markdown
+
+import (
+	"strings"
+)
+
+// Alignment of the columns of a table.
+const (
+	alignLeft = iota
+	alignCenter
+	alignRight
+)
+
+// isTableStart checks if a table starts at the given line: a row followed by a delimiter row.
+func isTableStart(lines []string, start int) bool {
+	return strings.Contains(lines[start], "|") && start+1 < len(lines) &&
+		strings.Contains(lines[start+1], "-") && tableSepRegex.MatchString(lines[start+1])
+}
+
+// tableCells splits a table row in its cells.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	cells := make([]string, 0)
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// table renders a table aligning its columns. The header is shown in bold and separated from the
+// rows with a line.
+func (r *renderer) table(lines []string, start int) ([]string, int) {
+	header := tableCells(lines[start])
+	alignments := make([]int, len(header))
+	for index, delimiter := range tableCells(lines[start+1]) {
+		if index >= len(alignments) {
+			break
+		}
+		switch {
+		case strings.HasPrefix(delimiter, ":") && strings.HasSuffix(delimiter, ":"):
+			alignments[index] = alignCenter
+		case strings.HasSuffix(delimiter, ":"):
+			alignments[index] = alignRight
+		}
+	}
+
+	rows := [][]string{header}
+	i := start + 2
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|"); i++ {
+		rows = append(rows, tableCells(lines[i]))
+	}
+	widths := make([]int, len(header))
+	for rowIndex, row := range rows {
+		cells := make([]string, len(header))
+		for index := range cells {
+			if index < len(row) {
+				cells[index] = r.inline.render(row[index])
+			}
+			if visibleLen(cells[index]) > widths[index] {
+				widths[index] = visibleLen(cells[index])
+			}
+		}
+		rows[rowIndex] = cells
+	}
+
+	result := make([]string, 0, len(rows)+1)
+	separator := make([]string, len(widths))
+	for index, width := range widths {
+		separator[index] = strings.Repeat("─", width)
+	}
+	for rowIndex, row := range rows {
+		cells := make([]string, len(row))
+		for index, cell := range row {
+			if rowIndex == 0 {
+				cell = styleBold + cell + styleBoldOff
+			}
+			cells[index] = align(cell, widths[index], alignments[index])
+		}
+		result = append(result, strings.TrimRight(strings.Join(cells, " │ "), " "))
+		if rowIndex == 0 {
+			result = append(result, styleDim+strings.Join(separator, "─┼─")+styleBoldOff)
+		}
+	}
+	return result, i
+}
+
+// align fills a cell with spaces up to the width of its column.
+func align(cell string, width int, alignment int) string {
+	missing := width - visibleLen(cell)
+	if missing <= 0 {
+		return cell
+	}
+	switch alignment {
+	case alignRight:
+		return strings.Repeat(" ", missing) + cell
+	case alignCenter:
+		return strings.Repeat(" ", missing/2) + cell + strings.Repeat(" ", missing-missing/2)
+	default:
+		return cell + strings.Repeat(" ", missing)
+	}
+}
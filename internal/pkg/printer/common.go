@@ -18,6 +18,9 @@ package printer
 
 import (
 	"fmt"
+	"io"
+	"os"
+
 	"github.com/napptive/nerrors/pkg/nerrors"
 	"github.com/rs/zerolog"
 )
@@ -33,11 +36,16 @@ type ResultPrinter interface {
 
 // GetPrinter creates a ResultPrinter attending to the user preferences.
 func GetPrinter(printerType string) (ResultPrinter, error) {
+	return GetPrinterTo(printerType, os.Stdout)
+}
+
+// GetPrinterTo creates a ResultPrinter attending to the user preferences that prints the results in the given writer.
+func GetPrinterTo(printerType string, out io.Writer) (ResultPrinter, error) {
 	switch printerType {
 	case "json":
-		return NewJSONPrinter()
+		return NewJSONPrinter(out)
 	case "table":
-		return NewTablePrinter()
+		return NewTablePrinter(out)
 	case "noPrinter":
 		return NewNoPrinter()
 	}
@@ -53,9 +61,28 @@ func PrintResultOrError(printer ResultPrinter, result interface{}, err error) er
 }
 
 func PrintError(err error) {
+	PrintErrorTo(os.Stdout, err)
+}
+
+// PrintErrorTo prints the error in the given writer.
+func PrintErrorTo(out io.Writer, err error) {
 	if zerolog.GlobalLevel() == zerolog.DebugLevel {
-		fmt.Println(nerrors.FromError(err).StackTraceToString())
+		fmt.Fprintln(out, nerrors.FromError(err).StackTraceToString())
 	} else {
-		fmt.Println(err.Error())
+		fmt.Fprintln(out, err.Error())
+	}
+}
+
+// HumanSize returns a human readable representation of a size in bytes.
+func HumanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
 	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
 }
@@ -19,22 +19,25 @@ package printer
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
 // JSONPrinter structure with the implementation required to print as JSON a given result.
 type JSONPrinter struct {
+	// out with the writer where the results are printed.
+	out io.Writer
 }
 
 // NewJSONPrinter build a new ResultPrinter whose output is the JSON representation of the object.
-func NewJSONPrinter() (ResultPrinter, error) {
-	return &JSONPrinter{}, nil
+func NewJSONPrinter(out io.Writer) (ResultPrinter, error) {
+	return &JSONPrinter{out: out}, nil
 }
 
 // Print the result.
 func (jp *JSONPrinter) Print(result interface{}) error {
 	res, err := json.Marshal(result)
 	if err == nil {
-		fmt.Println(string(res))
+		_, err = fmt.Fprintln(jp.out, string(res))
 	}
 	return err
 }
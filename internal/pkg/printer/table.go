@@ -19,10 +19,11 @@ package printer
 import (
 	"fmt"
 	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
-	"os"
+	"io"
 	"text/tabwriter"
 	"text/template"
 
+	"github.com/napptive/catalog-cli/v2/internal/pkg/markdown"
 	"github.com/napptive/nerrors/pkg/nerrors"
 )
 
@@ -41,17 +42,29 @@ const (
 
 // TablePrinter structure with the implementation required to print in a human readable table format a given result.
 type TablePrinter struct {
+	// out with the writer where the results are printed.
+	out io.Writer
 }
 
 // NewTablePrinter builds a new ResultPrinter whose output is a human readable table-like representation of the object.
-func NewTablePrinter() (ResultPrinter, error) {
-	return &TablePrinter{}, nil
+func NewTablePrinter(out io.Writer) (ResultPrinter, error) {
+	return &TablePrinter{out: out}, nil
 }
 
 func (tp *TablePrinter) toString(content []byte) string {
 	return string(content)
 }
 
+// fromMarkdown renders a markdown document for the terminal, returning it unchanged if the output
+// is not a terminal.
+func (tp *TablePrinter) fromMarkdown(content []byte) string {
+	width, isTerminal := terminalWidth(tp.out)
+	if !isTerminal {
+		return string(content)
+	}
+	return markdown.Render(content, width)
+}
+
 // fromApplicationSummary composes the application in a catalog as
 // namespace/appName:tag Medatada_Name
 func (tp *TablePrinter) fromApplicationSummary(app *grpc_catalog_go.ApplicationSummary) string {
@@ -76,18 +89,28 @@ func (tp *TablePrinter) Print(result interface{}) error {
 	}
 	t := template.New("TablePrinter").Funcs(template.FuncMap{
 		"toString":               tp.toString,
+		"fromMarkdown":           tp.fromMarkdown,
 		"fromApplicationSummary": tp.fromApplicationSummary,
+		"humanSize":              HumanSize,
 	})
 	t, err = t.Parse(*associatedTemplate)
 	if err != nil {
 		return nerrors.NewInternalErrorFrom(err, "cannot apply template")
 	}
-	w := tabwriter.NewWriter(os.Stdout, MinWidth, TabWidth, Padding, PaddingChar, TabWriterFlags)
+	w := tabwriter.NewWriter(tp.out, MinWidth, TabWidth, Padding, PaddingChar, TabWriterFlags)
 	if err := t.Execute(w, result); err != nil {
 		return err
 	}
 	w.Flush()
-	return nil
+	rawTemplate, exists := GetRawTemplate(result)
+	if !exists {
+		return nil
+	}
+	raw, err := template.New("TablePrinterRaw").Parse(*rawTemplate)
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "cannot apply template")
+	}
+	return raw.Execute(tp.out, result)
 }
 
 // PrintResultOrError prints the result using a given printer or the error.
@@ -0,0 +1,54 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Table printer tests", func() {
+
+	ginkgo.It("Should print the patches of a diff unchanged after the table", func() {
+		patch := "--- a/Makefile\n+++ b/Makefile\n@@ -1,2 +1,2 @@\n build:\n-\tgo build ./...\n+\tgo build\t-o bin/ ./...\n"
+		result := &entities.DiffResult{
+			From:        "ns/app:1.0",
+			To:          "ns/app:1.1",
+			NumModified: 1,
+			Additions:   1,
+			Deletions:   1,
+			Files: []*entities.FileDiff{
+				{Path: "Makefile", Status: entities.FileModified, Additions: 1, Deletions: 1, Patch: patch},
+				{Path: "logo.png", Status: entities.FileAdded, Binary: true},
+			},
+		}
+		var out bytes.Buffer
+		printer, err := NewTablePrinter(&out)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(printer.Print(result)).To(gomega.Succeed())
+
+		gomega.Expect(out.String()).To(gomega.HaveSuffix("\n" + patch))
+		table := strings.TrimSuffix(out.String(), patch)
+		gomega.Expect(table).NotTo(gomega.ContainSubstring("\t"))
+		gomega.Expect(table).To(gomega.ContainSubstring("Makefile"))
+		gomega.Expect(table).To(gomega.ContainSubstring("binary"))
+	})
+})
@@ -19,6 +19,7 @@ package printer
 import (
 	"reflect"
 
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
 	grpc_catalog_common_go "github.com/napptive/grpc-catalog-common-go"
 	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
 	"github.com/napptive/nerrors/pkg/nerrors"
@@ -29,8 +30,8 @@ const OpResponseTemplate = `STATUS	INFO
 {{.StatusName}}	{{.UserInfo}}
 `
 
-// InfoAppResponseTemplate with the table representation of an InfoAppResponse.
-const InfoAppResponseTemplate = `APP_ID	VISIBILITY	NAME
+// infoAppHeaderTemplate with the table representation of the metadata of an application.
+const infoAppHeaderTemplate = `APP_ID	VISIBILITY	NAME
 {{.Namespace}}/{{.ApplicationName}}:{{.Tag}}	{{if .Private}}Private{{else}}Public{{end}}	{{.Metadata.Name}}
 
 DESCRIPTION
@@ -41,10 +42,25 @@ DESCRIPTION
 {{range $name := .Metadata.Requires.Scopes}}{{$name}}
 {{end}}{{end}}{{if .Metadata.Requires.K8S}}K8S_ENTITIES
 {{range .Metadata.Requires.K8S}}{{.ApiVersion}}/{{.Kind}}
-{{end}}{{end}}
+{{end}}{{end}}`
+
+// infoAppReadmeTemplate with the table representation of the README of an application.
+const infoAppReadmeTemplate = `{{if .ReadmeFile}}
 README
-{{toString .ReadmeFile}}
-`
+{{fromMarkdown .ReadmeFile}}
+{{end}}`
+
+// InfoAppResponseTemplate with the table representation of an InfoAppResponse.
+const InfoAppResponseTemplate = infoAppHeaderTemplate + infoAppReadmeTemplate
+
+// ApplicationInfoTemplate with the table representation of an ApplicationInfo.
+const ApplicationInfoTemplate = infoAppHeaderTemplate + `
+FILE	SIZE
+{{range .Files}}{{.Path}}	{{humanSize .Size}}
+{{end}}
+FILES	TOTAL SIZE
+{{len .Files}}	{{humanSize .TotalSize}}
+` + infoAppReadmeTemplate
 
 const ApplicationListTemplate = `APPLICATION	VISIBILITY	NAME
 {{range $other, $app := .Applications}}{{fromApplicationSummary $app}}{{end}}`
@@ -53,15 +69,155 @@ const SummaryResponseTemplate = `NAMESPACES	APPLICATIONS	TAGS
 {{.NumNamespaces}}	{{.NumApplications}}	{{.NumTags}}
 `
 
+// LintResultTemplate with the table representation of a LintResult.
+const LintResultTemplate = `PATH	FILES	ERRORS	WARNINGS
+{{.Path}}	{{.NumFiles}}	{{.NumErrors}}	{{.NumWarnings}}
+{{if .Problems}}
+SEVERITY	LOCATION	MESSAGE
+{{range .Problems}}{{.Severity}}	{{.File}}{{if .Line}}:{{.Line}}{{end}}	{{.Message}}
+{{end}}{{end}}`
+
+// PushPlanTemplate with the table representation of a PushPlan.
+const PushPlanTemplate = `CATALOG	VISIBILITY	FILES	SIZE
+{{.CatalogURL}}	{{.Visibility}}	{{.NumFiles}}	{{humanSize .TotalSize}}
+
+APPLICATION
+{{range .ApplicationIDs}}{{.}}
+{{end}}
+DIGEST
+{{.Digest}}
+
+FILE	SIZE
+{{range .Files}}{{.Path}}	{{humanSize .Size}}
+{{end}}`
+
+// PushSummaryTemplate with the table representation of a PushSummary.
+const PushSummaryTemplate = `APPLICATION	STATUS	INFO
+{{range .Results}}{{.ApplicationID}}	{{.Status}}	{{.Info}}
+{{end}}`
+
+// PullResultTemplate with the table representation of a PullResult.
+const PullResultTemplate = `APPLICATION	PATH	DIGEST
+{{.ApplicationID}}	{{.Path}}	{{.Digest}}{{if .Verified}} (verified){{end}}{{if .Cached}} (cached){{end}}
+{{if .Files}}
+FILES
+{{range .Files}}{{.}}
+{{end}}{{end}}`
+
+// BulkPullSummaryTemplate with the table representation of a BulkPullSummary.
+const BulkPullSummaryTemplate = `APPLICATION	STATUS	PATH	INFO
+{{range .Results}}{{.ApplicationID}}	{{.Status}}	{{.Path}}	{{.Info}}
+{{end}}
+SUCCEEDED	FAILED	SKIPPED
+{{.NumSucceeded}}	{{.NumFailed}}	{{.NumSkipped}}
+`
+
+// CacheListTemplate with the table representation of a CacheList.
+const CacheListTemplate = `CACHE	SIZE
+{{.Path}}	{{humanSize .TotalSize}}
+
+APPLICATION	DIGEST	SIZE	LAST USED
+{{range .Entries}}{{.ApplicationID}}	{{if .Digest}}{{.Digest}}	{{humanSize .Size}}{{else}}-	-{{end}}	{{.LastUsed.Format "2006-01-02 15:04:05"}}
+{{end}}`
+
+// CacheCleanResultTemplate with the table representation of a CacheCleanResult.
+const CacheCleanResultTemplate = `CACHE	ENTRIES	BUNDLES	FREED
+{{.Path}}	{{.RemovedEntries}}	{{.RemovedBundles}}	{{humanSize .FreedBytes}}
+`
+
+// DiffResultTemplate with the table representation of a DiffResult.
+const DiffResultTemplate = `FROM	TO
+{{.From}}	{{.To}}
+
+FILE	STATUS	CHANGES
+{{range .Files}}{{.Path}}	{{.Status}}	{{if .Binary}}binary{{else}}+{{.Additions}} -{{.Deletions}}{{end}}
+{{end}}
+ADDED	REMOVED	MODIFIED	LINES
+{{.NumAdded}}	{{.NumRemoved}}	{{.NumModified}}	+{{.Additions}} -{{.Deletions}}
+{{if .Metadata}}
+METADATA	CHANGE	FROM	TO
+{{range .Metadata}}{{.Field}}	{{.Status}}	{{or .From "-"}}	{{or .To "-"}}
+{{end}}{{end}}`
+
+// DiffPatchTemplate with the patches of a DiffResult, printed after the table without aligning them.
+const DiffPatchTemplate = `{{range .Files}}{{if .Patch}}
+{{.Patch}}{{end}}{{end}}`
+
+// RemovePlanTemplate with the table representation of a RemovePlan.
+const RemovePlanTemplate = `CATALOG	ALL TAGS
+{{.CatalogURL}}	{{.AllTags}}
+
+APPLICATION	VISIBILITY	NAME
+{{range .Targets}}{{.ApplicationID}}	{{if .Private}}Private{{else}}Public{{end}}	{{.Name}}
+{{end}}`
+
+// PruneResultTemplate with the table representation of a PruneResult.
+const PruneResultTemplate = `APPLICATION	CATALOG	KEPT	REMOVED	FAILED
+{{.ApplicationID}}	{{.CatalogURL}}	{{.NumKept}}	{{.NumRemoved}}	{{.NumFailed}}
+
+TAG	ACTION	INFO
+{{range .Tags}}{{.Tag}}	{{.Action}}	{{.Info}}
+{{end}}`
+
+// BulkRemoveSummaryTemplate with the table representation of a BulkRemoveSummary.
+const BulkRemoveSummaryTemplate = `APPLICATION	STATUS	INFO
+{{range .Results}}{{.ApplicationID}}	{{.Status}}	{{.Info}}
+{{end}}
+SUCCEEDED	FAILED	SKIPPED
+{{.NumSucceeded}}	{{.NumFailed}}	{{.NumSkipped}}
+`
+
+// TrashListTemplate with the table representation of a TrashList.
+const TrashListTemplate = `TRASH
+{{.Path}}
+
+ENTRY	OPERATION	CREATED	APPLICATION	VISIBILITY
+{{range $entry := .Entries}}{{range .Applications}}{{$entry.Name}}	{{$entry.Operation}}	{{$entry.CreatedAt.Format "2006-01-02 15:04:05"}}	{{$entry.CatalogURL}}/{{.ApplicationID}}	{{if .Private}}Private{{else}}Public{{end}}
+{{end}}{{end}}`
+
+// TrashPruneResultTemplate with the table representation of a TrashPruneResult.
+const TrashPruneResultTemplate = `TRASH	ENTRIES	FREED
+{{.Path}}	{{len .RemovedEntries}}	{{humanSize .FreedBytes}}
+`
+
+// RestoreResultTemplate with the table representation of a RestoreResult.
+const RestoreResultTemplate = `ENTRY	CATALOG
+{{.Entry}}	{{.CatalogURL}}
+
+APPLICATION	STATUS	INFO
+{{range .Results}}{{.ApplicationID}}	{{.Status}}	{{.Info}}
+{{end}}`
+
 // structTemplates map associating type and template to print it.
 var structTemplates = map[reflect.Type]string{
 	reflect.TypeOf(&grpc_catalog_common_go.OpResponse{}):       OpResponseTemplate,
 	reflect.TypeOf(&grpc_catalog_go.InfoApplicationResponse{}): InfoAppResponseTemplate,
 	reflect.TypeOf(&grpc_catalog_go.ApplicationList{}):         ApplicationListTemplate,
 	reflect.TypeOf(&grpc_catalog_go.SummaryResponse{}):         SummaryResponseTemplate,
+	reflect.TypeOf(&entities.LintResult{}):                     LintResultTemplate,
+	reflect.TypeOf(&entities.PushPlan{}):                       PushPlanTemplate,
+	reflect.TypeOf(&entities.PushSummary{}):                    PushSummaryTemplate,
+	reflect.TypeOf(&entities.PullResult{}):                     PullResultTemplate,
+	reflect.TypeOf(&entities.BulkPullSummary{}):                BulkPullSummaryTemplate,
+	reflect.TypeOf(&entities.CacheList{}):                      CacheListTemplate,
+	reflect.TypeOf(&entities.CacheCleanResult{}):               CacheCleanResultTemplate,
+	reflect.TypeOf(&entities.DiffResult{}):                     DiffResultTemplate,
+	reflect.TypeOf(&entities.RemovePlan{}):                     RemovePlanTemplate,
+	reflect.TypeOf(&entities.BulkRemoveSummary{}):              BulkRemoveSummaryTemplate,
+	reflect.TypeOf(&entities.PruneResult{}):                    PruneResultTemplate,
+	reflect.TypeOf(&entities.TrashList{}):                      TrashListTemplate,
+	reflect.TypeOf(&entities.TrashPruneResult{}):               TrashPruneResultTemplate,
+	reflect.TypeOf(&entities.RestoreResult{}):                  RestoreResultTemplate,
+	reflect.TypeOf(&entities.ApplicationInfo{}):                ApplicationInfoTemplate,
 	//
 }
 
+// rawTemplates map associating type and template to print the content that follows the table,
+// written as is so tabs are not taken as column separators.
+var rawTemplates = map[reflect.Type]string{
+	reflect.TypeOf(&entities.DiffResult{}): DiffPatchTemplate,
+}
+
 // GetTemplate returns a template to print an arbitrary structure in table format.
 func GetTemplate(result interface{}) (*string, error) {
 	template, exists := structTemplates[reflect.TypeOf(result)]
@@ -70,3 +226,12 @@ func GetTemplate(result interface{}) (*string, error) {
 	}
 	return &template, nil
 }
+
+// GetRawTemplate returns the template to print the content of a structure that follows the table, if any.
+func GetRawTemplate(result interface{}) (*string, bool) {
+	template, exists := rawTemplates[reflect.TypeOf(result)]
+	if !exists {
+		return nil, false
+	}
+	return &template, true
+}
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package printer
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/mattn/go-isatty"
+)
+
+// defaultTerminalWidth with the width used when the size of the terminal cannot be determined.
+const defaultTerminalWidth = 80
+
+// terminalWidth returns the width of the terminal where the output is written. The output is not
+// considered a terminal if it is redirected or if styles are disabled with the NO_COLOR variable.
+func terminalWidth(out io.Writer) (int, bool) {
+	file, ok := out.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" || !(isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())) {
+		return 0, false
+	}
+	if width := windowWidth(file); width > 0 {
+		return width, true
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns, true
+	}
+	return defaultTerminalWidth, true
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/printer"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// RenderInterval with the minimum time between two updates of the progress line in a terminal.
+	RenderInterval = 100 * time.Millisecond
+	// LogInterval with the time between two progress entries when the output is not a terminal.
+	LogInterval = 5 * time.Second
+)
+
+// Tracker reports the progress of a transfer on stderr, so it never mixes with the results written on
+// stdout. When stderr is a terminal a progress line is updated in place, otherwise a structured log
+// entry is written periodically. A nil Tracker can be safely used.
+type Tracker struct {
+	// mu protects the counters as transfers may be reported from several goroutines.
+	mu sync.Mutex
+	// operation being tracked: push or pull.
+	operation string
+	// target with the application being transferred.
+	target string
+	// totalFiles with the expected number of files, 0 if unknown.
+	totalFiles int
+	// totalBytes with the expected number of bytes, 0 if unknown.
+	totalBytes int64
+	// files transferred so far.
+	files int
+	// bytes transferred so far.
+	bytes int64
+	// start of the transfer.
+	start time.Time
+	// lastReport with the time of the last update.
+	lastReport time.Time
+	// interactive is set if the progress is rendered in a terminal.
+	interactive bool
+	// out with the writer where the progress line is rendered in a terminal.
+	out io.Writer
+	// logger with the structured logger used when the output is not a terminal.
+	logger zerolog.Logger
+}
+
+// NewTracker creates a Tracker for a transfer. Use 0 as totals if they are not known in advance.
+func NewTracker(operation string, target string, totalFiles int, totalBytes int64) *Tracker {
+	interactive := isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())
+	return newTracker(operation, target, totalFiles, totalBytes, os.Stderr, interactive)
+}
+
+// newTracker creates a Tracker reporting the progress on the given writer.
+func newTracker(operation string, target string, totalFiles int, totalBytes int64, out io.Writer, interactive bool) *Tracker {
+	now := time.Now()
+	return &Tracker{
+		operation:   operation,
+		target:      target,
+		totalFiles:  totalFiles,
+		totalBytes:  totalBytes,
+		start:       now,
+		lastReport:  now,
+		interactive: interactive,
+		out:         out,
+		logger:      zerolog.New(out).With().Timestamp().Logger(),
+	}
+}
+
+// Add registers the transfer of a number of files and bytes.
+func (t *Tracker) Add(files int, bytes int64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.files += files
+	t.bytes += bytes
+	now := time.Now()
+	if t.interactive && now.Sub(t.lastReport) >= RenderInterval {
+		t.render(now, false)
+		t.lastReport = now
+	} else if !t.interactive && now.Sub(t.lastReport) >= LogInterval {
+		t.logProgress(now, "transfer in progress")
+		t.lastReport = now
+	}
+}
+
+// Done finishes the progress report. Transfers shorter than the log interval are not reported
+// when the output is not a terminal.
+func (t *Tracker) Done() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	if t.interactive {
+		t.render(now, true)
+	} else if now.Sub(t.start) >= LogInterval {
+		t.logProgress(now, "transfer finished")
+	}
+}
+
+// throughput returns the bytes per second transferred.
+func (t *Tracker) throughput(now time.Time) int64 {
+	elapsed := now.Sub(t.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(t.bytes) / elapsed)
+}
+
+// status returns the transferred files and bytes, and the throughput.
+func (t *Tracker) status(now time.Time) string {
+	files := fmt.Sprintf("%d", t.files)
+	if t.totalFiles > 0 {
+		files = fmt.Sprintf("%d/%d", t.files, t.totalFiles)
+	}
+	bytes := printer.HumanSize(t.bytes)
+	if t.totalBytes > 0 {
+		bytes = fmt.Sprintf("%s/%s", bytes, printer.HumanSize(t.totalBytes))
+	}
+	return fmt.Sprintf("%s files, %s, %s/s", files, bytes, printer.HumanSize(t.throughput(now)))
+}
+
+// render writes the progress line in the terminal.
+func (t *Tracker) render(now time.Time, final bool) {
+	line := fmt.Sprintf("\r\033[K%s %s: %s", t.operation, t.target, t.status(now))
+	if final {
+		line += "\n"
+	}
+	fmt.Fprint(t.out, line)
+}
+
+// logProgress writes a structured log entry with the state of the transfer when the output is not a terminal.
+func (t *Tracker) logProgress(now time.Time, message string) {
+	t.logger.Info().Str("operation", t.operation).Str("target", t.target).
+		Int("files", t.files).Int("total_files", t.totalFiles).
+		Int64("bytes", t.bytes).Int64("total_bytes", t.totalBytes).
+		Int64("throughput", t.throughput(now)).
+		Dur("elapsed", now.Sub(t.start)).Msg(message)
+}
@@ -0,0 +1,98 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Progress tests", func() {
+
+	var out *bytes.Buffer
+
+	ginkgo.BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	ginkgo.It("Should compute the throughput from the elapsed time", func() {
+		tracker := newTracker("push", "ns/app", 2, 4096, out, false)
+		gomega.Expect(tracker.throughput(tracker.start)).To(gomega.Equal(int64(0)))
+		tracker.bytes = 4096
+		gomega.Expect(tracker.throughput(tracker.start.Add(2 * time.Second))).To(gomega.Equal(int64(2048)))
+		gomega.Expect(tracker.throughput(tracker.start.Add(500 * time.Millisecond))).To(gomega.Equal(int64(8192)))
+		gomega.Expect(tracker.status(tracker.start.Add(2 * time.Second))).To(gomega.Equal("0/2 files, 4.0 KiB/4.0 KiB, 2.0 KiB/s"))
+	})
+
+	ginkgo.It("Should not report short transfers when the output is not a terminal", func() {
+		tracker := newTracker("pull", "ns/app", 0, 0, out, false)
+		tracker.Add(1, 100)
+		tracker.Done()
+		gomega.Expect(out.String()).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("Should write a structured entry per interval when the output is not a terminal", func() {
+		tracker := newTracker("pull", "ns/app", 3, 4096, out, false)
+		tracker.start = tracker.start.Add(-2 * LogInterval)
+		tracker.lastReport = tracker.start
+		tracker.Add(1, 1024)
+		tracker.Add(1, 1024)
+		tracker.Done()
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		gomega.Expect(lines).To(gomega.HaveLen(2))
+		entries := make([]map[string]interface{}, len(lines))
+		for i, line := range lines {
+			gomega.Expect(json.Unmarshal([]byte(line), &entries[i])).To(gomega.Succeed())
+			gomega.Expect(entries[i]["level"]).To(gomega.Equal("info"))
+			gomega.Expect(entries[i]["operation"]).To(gomega.Equal("pull"))
+			gomega.Expect(entries[i]["target"]).To(gomega.Equal("ns/app"))
+			gomega.Expect(entries[i]["total_files"]).To(gomega.Equal(float64(3)))
+			gomega.Expect(entries[i]["total_bytes"]).To(gomega.Equal(float64(4096)))
+			gomega.Expect(entries[i]).To(gomega.HaveKey("time"))
+		}
+		gomega.Expect(entries[0]["message"]).To(gomega.Equal("transfer in progress"))
+		gomega.Expect(entries[0]["files"]).To(gomega.Equal(float64(1)))
+		gomega.Expect(entries[0]["bytes"]).To(gomega.Equal(float64(1024)))
+		gomega.Expect(entries[1]["message"]).To(gomega.Equal("transfer finished"))
+		gomega.Expect(entries[1]["files"]).To(gomega.Equal(float64(2)))
+		gomega.Expect(entries[1]["bytes"]).To(gomega.Equal(float64(2048)))
+		gomega.Expect(entries[1]["throughput"]).To(gomega.BeNumerically("~", 204, 1))
+		gomega.Expect(out.String()).NotTo(gomega.ContainSubstring("\r"))
+	})
+
+	ginkgo.It("Should update the line in place in a terminal", func() {
+		tracker := newTracker("push", "ns/app", 1, 0, out, true)
+		tracker.lastReport = tracker.start.Add(-RenderInterval)
+		tracker.Add(1, 10)
+		tracker.Done()
+		gomega.Expect(out.String()).To(gomega.HavePrefix("\r\033[Kpush ns/app: 1/1 files, 10 B, "))
+		gomega.Expect(strings.Count(out.String(), "\r\033[K")).To(gomega.Equal(2))
+		gomega.Expect(out.String()).To(gomega.HaveSuffix("\n"))
+	})
+
+	ginkgo.It("Should ignore a nil tracker", func() {
+		var tracker *Tracker
+		tracker.Add(1, 1)
+		tracker.Done()
+	})
+})
@@ -0,0 +1,38 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Prompt tests", func() {
+
+	ginkgo.It("Should only accept explicit confirmations", func() {
+		for answer, expected := range map[string]bool{"y\n": true, "YES\n": true, " yes ": true, "\n": false, "n\n": false, "": false, "sure\n": false} {
+			var out bytes.Buffer
+			confirmed, err := Confirm(strings.NewReader(answer), &out, "Remove?")
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(confirmed).To(gomega.Equal(expected), "answer %q", answer)
+			gomega.Expect(out.String()).To(gomega.Equal("Remove? [y/N]: "))
+		}
+	})
+})
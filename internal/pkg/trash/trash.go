@@ -0,0 +1,224 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// manifestFile with the name of the file describing an entry.
+	manifestFile = "manifest.json"
+	// bundleSuffix with the extension of the saved applications.
+	bundleSuffix = ".tgz"
+	// entryNameFormat with the format of the creation time used to name the entries.
+	entryNameFormat = "20060102-150405"
+)
+
+// Trash stores the applications saved before a destructive operation. Each entry is a directory
+// named after its creation time, <timestamp>/manifest.json, containing a tgz file per application tag.
+type Trash struct {
+	// root directory of the trash.
+	root string
+}
+
+// New creates a trash in the given directory.
+func New(root string) *Trash {
+	return &Trash{root: root}
+}
+
+// DefaultDir returns the default location of the trash: ~/.napptive/trash
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nerrors.NewInternalErrorFrom(err, "unable to determine user home")
+	}
+	return filepath.Join(home, ".napptive", "trash"), nil
+}
+
+// Path returns the root directory of the trash.
+func (t *Trash) Path() string {
+	return t.root
+}
+
+// Create adds an empty entry to the trash.
+func (t *Trash) Create(operation string, catalogURL string) (*entities.TrashEntry, error) {
+	if err := os.MkdirAll(t.root, 0700); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to create trash directory %s", t.root)
+	}
+	now := time.Now()
+	base := now.Format(entryNameFormat)
+	name := base
+	for i := 2; ; i++ {
+		err := os.Mkdir(filepath.Join(t.root, name), 0700)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to create trash entry")
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	entry := &entities.TrashEntry{Name: name, Operation: operation, CatalogURL: catalogURL, CreatedAt: now, Applications: make([]*entities.TrashedApplication, 0)}
+	if err := t.save(entry); err != nil {
+		_ = t.Remove(entry)
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Remove deletes an entry and the applications saved in it.
+func (t *Trash) Remove(entry *entities.TrashEntry) error {
+	if err := os.RemoveAll(filepath.Join(t.root, entry.Name)); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to remove trash entry %s", entry.Name)
+	}
+	return nil
+}
+
+// AddBundle saves an application tag in an entry.
+func (t *Trash) AddBundle(entry *entities.TrashEntry, applicationID string, private bool, digest string, content []byte) error {
+	file := strings.NewReplacer("/", "_", ":", "_", `\`, "_").Replace(applicationID) + bundleSuffix
+	if err := os.WriteFile(filepath.Join(t.root, entry.Name, file), content, 0600); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to save %s in the trash", applicationID)
+	}
+	entry.Applications = append(entry.Applications, &entities.TrashedApplication{ApplicationID: applicationID, Private: private, Digest: digest, File: file})
+	return t.save(entry)
+}
+
+// BundlePath returns the path of the tgz file of an application saved in an entry.
+func (t *Trash) BundlePath(entry *entities.TrashEntry, application *entities.TrashedApplication) string {
+	return filepath.Join(t.root, entry.Name, filepath.Base(application.File))
+}
+
+// save writes the manifest of an entry.
+func (t *Trash) save(entry *entities.TrashEntry) error {
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to encode trash entry")
+	}
+	if err := os.WriteFile(filepath.Join(t.root, entry.Name, manifestFile), content, 0600); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write trash entry %s", entry.Name)
+	}
+	return nil
+}
+
+// Get returns an entry of the trash.
+func (t *Trash) Get(name string) (*entities.TrashEntry, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, nerrors.NewInvalidArgumentError("invalid trash entry %q", name)
+	}
+	content, err := os.ReadFile(filepath.Join(t.root, name, manifestFile))
+	if os.IsNotExist(err) {
+		return nil, nerrors.NewNotFoundError("trash entry %s not found in %s", name, t.root)
+	}
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read trash entry %s", name)
+	}
+	entry := &entities.TrashEntry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "invalid trash entry %s", name)
+	}
+	entry.Name = name
+	return entry, nil
+}
+
+// List returns the entries of the trash sorted by creation time.
+func (t *Trash) List() (*entities.TrashList, error) {
+	result := &entities.TrashList{Path: t.root, Entries: make([]*entities.TrashEntry, 0)}
+	dirs, err := os.ReadDir(t.root)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read trash %s", t.root)
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		entry, err := t.Get(dir.Name())
+		if err != nil {
+			log.Warn().Err(err).Str("entry", dir.Name()).Msg("ignoring invalid trash entry")
+			continue
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	sort.Slice(result.Entries, func(i, j int) bool {
+		return result.Entries[i].CreatedAt.Before(result.Entries[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Prune removes the entries created before the given duration.
+func (t *Trash) Prune(maxAge time.Duration) (*entities.TrashPruneResult, error) {
+	list, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+	result := &entities.TrashPruneResult{Path: t.root, RemovedEntries: make([]string, 0)}
+	limit := time.Now().Add(-maxAge)
+	for _, entry := range list.Entries {
+		if !entry.CreatedAt.Before(limit) {
+			continue
+		}
+		dir := filepath.Join(t.root, entry.Name)
+		size, err := dirSize(dir)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.Remove(entry); err != nil {
+			return nil, err
+		}
+		result.RemovedEntries = append(result.RemovedEntries, entry.Name)
+		result.FreedBytes += size
+	}
+	return result, nil
+}
+
+// dirSize returns the size of the files of a directory.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, nerrors.NewInternalErrorFrom(err, "unable to read %s", dir)
+	}
+	return size, nil
+}
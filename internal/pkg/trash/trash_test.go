@@ -0,0 +1,108 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Trash tests", func() {
+
+	var root string
+	var localTrash *Trash
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-trash")
+		gomega.Expect(err).To(gomega.Succeed())
+		root = dir
+		localTrash = New(filepath.Join(root, "trash"))
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	ginkgo.It("Should save and read the applications of an entry", func() {
+		entry, err := localTrash.Create("remove", "catalog.example.com:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(localTrash.AddBundle(entry, "ns/app:1.0", true, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+
+		read, err := localTrash.Get(entry.Name)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(read.Operation).To(gomega.Equal("remove"))
+		gomega.Expect(read.CatalogURL).To(gomega.Equal("catalog.example.com:7060"))
+		gomega.Expect(read.Applications).To(gomega.HaveLen(1))
+		gomega.Expect(read.Applications[0].ApplicationID).To(gomega.Equal("ns/app:1.0"))
+		gomega.Expect(read.Applications[0].Private).To(gomega.BeTrue())
+		content, err := os.ReadFile(localTrash.BundlePath(read, read.Applications[0]))
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("content"))
+	})
+
+	ginkgo.It("Should create a different entry for each operation", func() {
+		first, err := localTrash.Create("remove", "catalog.example.com:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+		second, err := localTrash.Create("prune", "catalog.example.com:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(first.Name).NotTo(gomega.Equal(second.Name))
+
+		list, err := localTrash.List()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(list.Entries).To(gomega.HaveLen(2))
+		gomega.Expect(list.Entries[0].Name).To(gomega.Equal(first.Name))
+	})
+
+	ginkgo.It("Should list an empty trash that does not exist", func() {
+		list, err := localTrash.List()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(list.Entries).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("Should refuse entry names outside the trash", func() {
+		for _, name := range []string{"", ".", "..", "../entry", "a/b"} {
+			_, err := localTrash.Get(name)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		}
+		_, err := localTrash.Get("missing")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("Should remove the entries older than the retention", func() {
+		old, err := localTrash.Create("remove", "catalog.example.com:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(localTrash.AddBundle(old, "ns/app:1.0", false, "sha256:abc", []byte("content"))).To(gomega.Succeed())
+		old.CreatedAt = time.Now().Add(-48 * time.Hour)
+		gomega.Expect(localTrash.save(old)).To(gomega.Succeed())
+		recent, err := localTrash.Create("remove", "catalog.example.com:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+
+		result, err := localTrash.Prune(24 * time.Hour)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(result.RemovedEntries).To(gomega.ConsistOf(old.Name))
+		gomega.Expect(result.FreedBytes).To(gomega.BeNumerically(">", 7))
+
+		list, err := localTrash.List()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(list.Entries).To(gomega.HaveLen(1))
+		gomega.Expect(list.Entries[0].Name).To(gomega.Equal(recent.Name))
+	})
+})
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+)
+
+// CacheEntry with the information stored in the local cache for an application tag.
+type CacheEntry struct {
+	// CatalogURL with the address of the catalog storing the application.
+	CatalogURL string `json:"catalog_url"`
+	// Namespace of the application.
+	Namespace string `json:"namespace"`
+	// ApplicationName with the name of the application.
+	ApplicationName string `json:"application_name"`
+	// Tag of the application.
+	Tag string `json:"tag"`
+	// Digest of the application content, empty if only the information of the application is cached.
+	Digest string `json:"digest,omitempty"`
+	// PushedDigest with the digest of the content last pushed under the tag from this machine.
+	PushedDigest string `json:"pushed_digest,omitempty"`
+	// Size in bytes of the cached bundle.
+	Size int64 `json:"size"`
+	// CachedAt with the time when the entry was updated from the catalog.
+	CachedAt time.Time `json:"cached_at"`
+	// LastUsed with the last time the entry was read.
+	LastUsed time.Time `json:"last_used"`
+	// Info with the last information of the application returned by the catalog, if any.
+	Info *grpc_catalog_go.InfoApplicationResponse `json:"info,omitempty"`
+}
+
+// ApplicationID returns the identifier of the cached application.
+func (ce *CacheEntry) ApplicationID() string {
+	return fmt.Sprintf("%s/%s/%s:%s", ce.CatalogURL, ce.Namespace, ce.ApplicationName, ce.Tag)
+}
+
+// CacheList with the content of the local cache.
+type CacheList struct {
+	// Path of the cache directory.
+	Path string `json:"path"`
+	// TotalSize with the size in bytes of the cached bundles.
+	TotalSize int64 `json:"total_size"`
+	// Entries of the cache.
+	Entries []*CacheEntry `json:"entries"`
+}
+
+// CacheCleanResult with the result of removing content from the local cache.
+type CacheCleanResult struct {
+	// Path of the cache directory.
+	Path string `json:"path"`
+	// RemovedEntries with the number of application tags removed.
+	RemovedEntries int `json:"removed_entries"`
+	// RemovedBundles with the number of bundles removed.
+	RemovedBundles int `json:"removed_bundles"`
+	// FreedBytes with the size of the removed bundles.
+	FreedBytes int64 `json:"freed_bytes"`
+}
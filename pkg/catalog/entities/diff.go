@@ -0,0 +1,82 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+const (
+	// FileAdded with the status of a file that only exists in the target application.
+	FileAdded = "added"
+	// FileRemoved with the status of a file that only exists in the source application.
+	FileRemoved = "removed"
+	// FileModified with the status of a file whose content has changed.
+	FileModified = "modified"
+)
+
+// FileDiff with the changes of a file.
+type FileDiff struct {
+	// Path of the file relative to the application.
+	Path string `json:"path"`
+	// Status of the file: added, removed or modified.
+	Status string `json:"status"`
+	// Binary is set if the content is not text, no patch is computed in that case.
+	Binary bool `json:"binary"`
+	// Additions with the number of lines added.
+	Additions int `json:"additions"`
+	// Deletions with the number of lines removed.
+	Deletions int `json:"deletions"`
+	// Patch with the unified diff of the file, unless only the summary is requested.
+	Patch string `json:"patch,omitempty"`
+}
+
+// MetadataChange with a change in the application metadata.
+type MetadataChange struct {
+	// Field of the metadata: name, version, requires.traits, requires.scopes or requires.k8s.
+	Field string `json:"field"`
+	// Status of the change: added, removed or modified.
+	Status string `json:"status"`
+	// From with the previous value, if any.
+	From string `json:"from,omitempty"`
+	// To with the new value, if any.
+	To string `json:"to,omitempty"`
+}
+
+// DiffResult with the differences between two versions of an application.
+type DiffResult struct {
+	// From with the application used as source.
+	From string `json:"from"`
+	// To with the application compared with the source.
+	To string `json:"to"`
+	// NumAdded with the number of files added.
+	NumAdded int `json:"num_added"`
+	// NumRemoved with the number of files removed.
+	NumRemoved int `json:"num_removed"`
+	// NumModified with the number of files modified.
+	NumModified int `json:"num_modified"`
+	// Additions with the total number of lines added.
+	Additions int `json:"additions"`
+	// Deletions with the total number of lines removed.
+	Deletions int `json:"deletions"`
+	// Files with the changed files sorted by path.
+	Files []*FileDiff `json:"files"`
+	// Metadata with the changes in the application metadata, shown separately as they may require
+	// changes in the target environment.
+	Metadata []*MetadataChange `json:"metadata"`
+}
+
+// HasChanges checks if any file is different.
+func (dr *DiffResult) HasChanges() bool {
+	return len(dr.Files) > 0
+}
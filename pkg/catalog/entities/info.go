@@ -0,0 +1,38 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+import (
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+)
+
+// ApplicationFile with a file of an application stored in the catalog.
+type ApplicationFile struct {
+	// Path of the file relative to the application root.
+	Path string `json:"path"`
+	// Size of the file in bytes.
+	Size int64 `json:"size"`
+}
+
+// ApplicationInfo with the information of an application and the files it contains.
+type ApplicationInfo struct {
+	*grpc_catalog_go.InfoApplicationResponse
+	// Files of the application sorted by path.
+	Files []*ApplicationFile `json:"files"`
+	// TotalSize with the size in bytes of all the files.
+	TotalSize int64 `json:"total_size"`
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+import "fmt"
+
+const (
+	// LintError with the severity of the problems that prevent an application from being pushed.
+	LintError = "ERROR"
+	// LintWarning with the severity of the problems that do not prevent an application from being pushed.
+	LintWarning = "WARNING"
+)
+
+// LintProblem with a problem found while validating an application.
+type LintProblem struct {
+	// File with the path of the file relative to the application directory.
+	File string `json:"file"`
+	// Line with the line of the file in which the problem was found, 0 if unknown.
+	Line int `json:"line,omitempty"`
+	// Severity of the problem: ERROR or WARNING.
+	Severity string `json:"severity"`
+	// Message describing the problem.
+	Message string `json:"message"`
+}
+
+// LintResult with the result of validating an application.
+type LintResult struct {
+	// Path with the application directory.
+	Path string `json:"path"`
+	// NumFiles with the number of files that would be pushed.
+	NumFiles int `json:"num_files"`
+	// NumErrors with the number of problems with ERROR severity.
+	NumErrors int `json:"num_errors"`
+	// NumWarnings with the number of problems with WARNING severity.
+	NumWarnings int `json:"num_warnings"`
+	// Problems found in the application.
+	Problems []*LintProblem `json:"problems,omitempty"`
+}
+
+// AddProblem appends a new problem to the result updating the counters.
+func (lr *LintResult) AddProblem(severity string, file string, line int, format string, args ...interface{}) {
+	if severity == LintError {
+		lr.NumErrors++
+	} else {
+		lr.NumWarnings++
+	}
+	lr.Problems = append(lr.Problems, &LintProblem{
+		File:     file,
+		Line:     line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,69 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+const (
+	// PruneKeep with the action of a tag that is kept.
+	PruneKeep = "KEEP"
+	// PruneRemove with the action of a tag that will be removed.
+	PruneRemove = "REMOVE"
+	// PruneRemoved with the action of a tag that has been removed.
+	PruneRemoved = "REMOVED"
+	// PruneFailed with the action of a tag that could not be removed.
+	PruneFailed = "FAILED"
+)
+
+// PruneTag with the action applied to a tag by a prune operation.
+type PruneTag struct {
+	// Tag of the application.
+	Tag string `json:"tag"`
+	// Action applied to the tag: KEEP, REMOVE, REMOVED or FAILED.
+	Action string `json:"action"`
+	// Info with the reason to keep the tag or the error, if any.
+	Info string `json:"info,omitempty"`
+}
+
+// PruneResult with the tags kept and removed by a prune operation.
+type PruneResult struct {
+	// ApplicationID with the pruned application.
+	ApplicationID string `json:"application_id"`
+	// CatalogURL with the address of the catalog.
+	CatalogURL string `json:"catalog_url"`
+	// NumKept with the number of tags kept.
+	NumKept int `json:"num_kept"`
+	// NumRemoved with the number of tags removed, or to be removed.
+	NumRemoved int `json:"num_removed"`
+	// NumFailed with the number of tags that could not be removed.
+	NumFailed int `json:"num_failed"`
+	// Tags ordered from the most recent one.
+	Tags []*PruneTag `json:"tags"`
+}
+
+// Count updates the number of tags of each action.
+func (pr *PruneResult) Count() {
+	pr.NumKept, pr.NumRemoved, pr.NumFailed = 0, 0, 0
+	for _, tag := range pr.Tags {
+		switch tag.Action {
+		case PruneKeep:
+			pr.NumKept++
+		case PruneRemove, PruneRemoved:
+			pr.NumRemoved++
+		case PruneFailed:
+			pr.NumFailed++
+		}
+	}
+}
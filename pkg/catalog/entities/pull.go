@@ -0,0 +1,68 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+// PullResult with the result of pulling an application.
+type PullResult struct {
+	// ApplicationID with the pulled application.
+	ApplicationID string `json:"application_id"`
+	// Path where the tgz file or the extracted application has been written.
+	Path string `json:"path"`
+	// Digest of the application content.
+	Digest string `json:"digest"`
+	// Verified is set if the digest matched the expected one.
+	Verified bool `json:"verified"`
+	// Cached is set if the application has been read from the local cache.
+	Cached bool `json:"cached"`
+	// Files with the paths of the extracted files, if any.
+	Files []string `json:"files,omitempty"`
+}
+
+const (
+	// PullSuccess with the status of an application that has been pulled.
+	PullSuccess = "SUCCESS"
+	// PullFailed with the status of an application that could not be pulled.
+	PullFailed = "FAILED"
+	// PullSkipped with the status of an application listed more than once in a bulk pull.
+	PullSkipped = "SKIPPED"
+)
+
+// BulkPullResult with the result of pulling one of the applications of a bulk pull.
+type BulkPullResult struct {
+	// ApplicationID with the pulled application.
+	ApplicationID string `json:"application_id"`
+	// Status of the operation: SUCCESS, FAILED or SKIPPED.
+	Status string `json:"status"`
+	// Path where the tgz file has been written.
+	Path string `json:"path"`
+	// Digest of the application content.
+	Digest string `json:"digest,omitempty"`
+	// Info with the error, if any.
+	Info string `json:"info,omitempty"`
+}
+
+// BulkPullSummary with the results of pulling several applications.
+type BulkPullSummary struct {
+	// NumSucceeded with the number of applications pulled.
+	NumSucceeded int `json:"num_succeeded"`
+	// NumFailed with the number of applications that could not be pulled.
+	NumFailed int `json:"num_failed"`
+	// NumSkipped with the number of applications skipped because they were listed more than once.
+	NumSkipped int `json:"num_skipped"`
+	// Results with one entry per application.
+	Results []*BulkPullResult `json:"results"`
+}
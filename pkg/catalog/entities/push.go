@@ -0,0 +1,70 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+// BundleFile with the path and size of a file of an application.
+type BundleFile struct {
+	// Path of the file relative to the application root.
+	Path string `json:"path"`
+	// Size of the file in bytes.
+	Size int64 `json:"size"`
+}
+
+const (
+	// PushSuccess with the status of an application that has been pushed.
+	PushSuccess = "SUCCESS"
+	// PushUnchanged with the status of an application that has not been pushed because the catalog contains the same content.
+	PushUnchanged = "UNCHANGED"
+	// PushFailed with the status of an application that could not be pushed.
+	PushFailed = "FAILED"
+)
+
+// PushPlan with the description of what a push operation would upload.
+type PushPlan struct {
+	// CatalogURL with the address of the target catalog.
+	CatalogURL string `json:"catalog_url"`
+	// ApplicationIDs with the resolved namespace/appName:tag of each target.
+	ApplicationIDs []string `json:"application_ids"`
+	// Visibility of the application: Public or Private.
+	Visibility string `json:"visibility"`
+	// Digest of the application content.
+	Digest string `json:"digest"`
+	// NumFiles with the number of files.
+	NumFiles int `json:"num_files"`
+	// TotalSize with the sum of the sizes of the files in bytes.
+	TotalSize int64 `json:"total_size"`
+	// Files that would be uploaded.
+	Files []*BundleFile `json:"files"`
+}
+
+// PushResult with the result of pushing an application under a tag.
+type PushResult struct {
+	// ApplicationID with the target application.
+	ApplicationID string `json:"application_id"`
+	// Status of the operation: SUCCESS, UNCHANGED or FAILED.
+	Status string `json:"status"`
+	// Info with the message returned by the catalog or the error.
+	Info string `json:"info"`
+}
+
+// PushSummary with the results of pushing an application under several tags.
+type PushSummary struct {
+	// Digest of the pushed content.
+	Digest string `json:"digest"`
+	// Results with one entry per tag.
+	Results []*PushResult `json:"results"`
+}
@@ -0,0 +1,64 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+// RemoveTarget with an application tag affected by a remove operation.
+type RemoveTarget struct {
+	// ApplicationID with the application tag.
+	ApplicationID string `json:"application_id"`
+	// Name of the application in its metadata.
+	Name string `json:"name"`
+	// Private indicates that the application is only visible to the owner.
+	Private bool `json:"private"`
+}
+
+// RemovePlan with the application tags that a remove operation deletes.
+type RemovePlan struct {
+	// CatalogURL with the address of the catalog.
+	CatalogURL string `json:"catalog_url"`
+	// AllTags is set if all the tags of the application are removed.
+	AllTags bool `json:"all_tags"`
+	// Targets with the application tags.
+	Targets []*RemoveTarget `json:"targets"`
+}
+
+const (
+	// RemoveSuccess with the status of an application that has been removed.
+	RemoveSuccess = "SUCCESS"
+	// RemoveFailed with the status of an application that could not be removed.
+	RemoveFailed = "FAILED"
+)
+
+// BulkRemoveResult with the result of removing one of the applications of a bulk remove.
+type BulkRemoveResult struct {
+	// ApplicationID with the removed application.
+	ApplicationID string `json:"application_id"`
+	// Status of the operation: SUCCESS or FAILED.
+	Status string `json:"status"`
+	// Info with the error, if any.
+	Info string `json:"info,omitempty"`
+}
+
+// BulkRemoveSummary with the results of removing several applications.
+type BulkRemoveSummary struct {
+	// NumSucceeded with the number of applications removed.
+	NumSucceeded int `json:"num_succeeded"`
+	// NumFailed with the number of applications that could not be removed.
+	NumFailed int `json:"num_failed"`
+	// Results with one entry per application.
+	Results []*BulkRemoveResult `json:"results"`
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+import "time"
+
+// TrashedApplication with an application tag saved in the trash.
+type TrashedApplication struct {
+	// ApplicationID with the application tag: namespace/appName:tag.
+	ApplicationID string `json:"application_id"`
+	// Private with the visibility of the application when it was saved.
+	Private bool `json:"private"`
+	// Digest of the application content.
+	Digest string `json:"digest"`
+	// File with the name of the tgz file inside the trash entry.
+	File string `json:"file"`
+}
+
+// TrashEntry with the applications saved before a destructive operation.
+type TrashEntry struct {
+	// Name of the entry, used to restore it.
+	Name string `json:"name"`
+	// Operation that triggered the backup.
+	Operation string `json:"operation"`
+	// CatalogURL with the address of the catalog storing the applications.
+	CatalogURL string `json:"catalog_url"`
+	// CreatedAt with the creation time of the entry.
+	CreatedAt time.Time `json:"created_at"`
+	// Applications saved in the entry.
+	Applications []*TrashedApplication `json:"applications"`
+}
+
+// TrashList with the content of the trash.
+type TrashList struct {
+	// Path of the trash directory.
+	Path string `json:"path"`
+	// Entries of the trash sorted by creation time.
+	Entries []*TrashEntry `json:"entries"`
+}
+
+// TrashPruneResult with the result of removing old entries from the trash.
+type TrashPruneResult struct {
+	// Path of the trash directory.
+	Path string `json:"path"`
+	// RemovedEntries with the names of the removed entries.
+	RemovedEntries []string `json:"removed_entries"`
+	// FreedBytes with the size of the removed files.
+	FreedBytes int64 `json:"freed_bytes"`
+}
+
+// RestoreResult with the result of restoring the applications of a trash entry.
+type RestoreResult struct {
+	// Entry with the name of the restored trash entry.
+	Entry string `json:"entry"`
+	// CatalogURL with the address of the catalog where the applications have been pushed.
+	CatalogURL string `json:"catalog_url"`
+	// Results with one entry per application tag.
+	Results []*PushResult `json:"results"`
+}
@@ -0,0 +1,314 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// StdinPath with the application path used to read a tar stream from the standard input.
+const StdinPath = "-"
+
+// gzipMagic with the first bytes of a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// prepareApplicationPath returns a directory with the application files. Archives and tar streams
+// received on stdin are extracted in a temporary directory that is removed by the returned cleanup function.
+func prepareApplicationPath(appPath string) (string, func(), error) {
+	noCleanup := func() {}
+	lower := strings.ToLower(appPath)
+	isTar := strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tar")
+	isZip := strings.HasSuffix(lower, ".zip")
+	if appPath != StdinPath && !isTar && !isZip {
+		return appPath, noCleanup, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "catalog-push-")
+	if err != nil {
+		return "", noCleanup, nerrors.NewInternalErrorFrom(err, "unable to create temporary directory")
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warn().Err(err).Str("path", tempDir).Msg("unable to remove temporary directory")
+		}
+	}
+
+	switch {
+	case appPath == StdinPath:
+		_, err = extractTar(os.Stdin, "stdin", tempDir, "")
+	case isZip:
+		err = extractZip(appPath, tempDir)
+	default:
+		var file *os.File
+		file, err = os.Open(appPath)
+		if err != nil {
+			err = nerrors.NewInvalidArgumentError("unable to open archive %s", appPath)
+			break
+		}
+		defer file.Close()
+		_, err = extractTar(file, appPath, tempDir, "")
+	}
+	if err != nil {
+		cleanup()
+		return "", noCleanup, err
+	}
+	log.Debug().Str("archive", appPath).Str("path", tempDir).Msg("archive extracted")
+	return tempDir, cleanup, nil
+}
+
+// sanitizeEntryPath validates the name of an archive entry returning a clean relative path. Absolute
+// paths and paths with .. components are rejected.
+func sanitizeEntryPath(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(slashed, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", nerrors.NewInvalidArgumentError("invalid entry %q: absolute paths are not allowed", name)
+	}
+	for _, segment := range strings.Split(slashed, "/") {
+		if segment == ".." {
+			return "", nerrors.NewInvalidArgumentError("invalid entry %q: paths with .. are not allowed", name)
+		}
+	}
+	return path.Clean(slashed), nil
+}
+
+// openTar returns a reader of a tar stream, decompressing it if it starts with the gzip magic bytes.
+// The returned function releases the decompressor.
+func openTar(reader io.Reader, source string) (*tar.Reader, func(), error) {
+	buffered := bufio.NewReader(reader)
+	if magic, err := buffered.Peek(len(gzipMagic)); err == nil && string(magic) == string(gzipMagic) {
+		gzReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, nil, nerrors.NewInvalidArgumentError("unable to decompress %s: %s", source, err.Error())
+		}
+		return tar.NewReader(gzReader), func() { _ = gzReader.Close() }, nil
+	}
+	return tar.NewReader(buffered), func() {}, nil
+}
+
+// extractTar extracts a tar stream, compressed with gzip or not, in the target directory returning
+// the relative paths of the files written. Symbolic links are followed, skipped or rejected according
+// to the symlinks policy, and are not supported if it is empty. Followed links are created once the
+// rest of the entries have been written so no file is written through them.
+func extractTar(reader io.Reader, source string, targetDir string, symlinks string) ([]string, error) {
+	tarReader, closeTar, err := openTar(reader, source)
+	if err != nil {
+		return nil, err
+	}
+	defer closeTar()
+
+	var files []string
+	var links []*tar.Header
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			for _, link := range links {
+				if err := createSymlink(targetDir, link.Name, link.Linkname); err != nil {
+					return nil, err
+				}
+			}
+			return files, nil
+		}
+		if err != nil {
+			return nil, nerrors.NewInvalidArgumentError("unable to read %s: %s", source, err.Error())
+		}
+		entryPath, err := sanitizeEntryPath(header.Name)
+		if err != nil {
+			return nil, err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = createDir(targetDir, entryPath)
+		case tar.TypeReg, tar.TypeRegA:
+			err = createFile(targetDir, entryPath, tarReader)
+			files = append(files, entryPath)
+		case tar.TypeXGlobalHeader:
+			// global headers contain archive metadata, such as the commit added by git archive
+			continue
+		case tar.TypeSymlink:
+			switch symlinks {
+			case SymlinksFollow:
+				err = checkSymlinkTarget(entryPath, header.Linkname, source)
+				links = append(links, &tar.Header{Name: entryPath, Linkname: header.Linkname})
+			case SymlinksSkip:
+				log.Debug().Str("path", entryPath).Str("source", source).Msg("symbolic link skipped")
+			case SymlinksError:
+				err = nerrors.NewFailedPreconditionError("symbolic link %s found in %s, use --symlinks to follow or skip links", entryPath, source)
+			default:
+				err = nerrors.NewInvalidArgumentError("invalid entry %q in %s: only files and directories are supported", header.Name, source)
+			}
+		default:
+			err = nerrors.NewInvalidArgumentError("invalid entry %q in %s: only files and directories are supported", header.Name, source)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// archiveFiles returns the regular files contained in a tar stream, compressed with gzip or not.
+func archiveFiles(data []byte, source string) ([]*grpc_catalog_go.FileInfo, error) {
+	tarReader, closeTar, err := openTar(bytes.NewReader(data), source)
+	if err != nil {
+		return nil, err
+	}
+	defer closeTar()
+
+	var files []*grpc_catalog_go.FileInfo
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, nerrors.NewInvalidArgumentError("unable to read %s: %s", source, err.Error())
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, nerrors.NewInvalidArgumentError("unable to read %s: %s", source, err.Error())
+		}
+		files = append(files, &grpc_catalog_go.FileInfo{Path: header.Name, Data: content})
+	}
+}
+
+// createArchive returns a tgz stream with the given files.
+func createArchive(files []*grpc_catalog_go.FileInfo) ([]byte, error) {
+	var buffer bytes.Buffer
+	gzWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzWriter)
+	for _, file := range files {
+		header := &tar.Header{Name: file.Path, Mode: 0644, Size: int64(len(file.Data)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to create archive")
+		}
+		if _, err := tarWriter.Write(file.Data); err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to create archive")
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to create archive")
+	}
+	if err := gzWriter.Close(); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to create archive")
+	}
+	return buffer.Bytes(), nil
+}
+
+// extractZip extracts a zip file in the target directory.
+func extractZip(source string, targetDir string) error {
+	zipReader, err := zip.OpenReader(source)
+	if err != nil {
+		return nerrors.NewInvalidArgumentError("unable to open archive %s: %s", source, err.Error())
+	}
+	defer zipReader.Close()
+
+	for _, entry := range zipReader.File {
+		entryPath, err := sanitizeEntryPath(entry.Name)
+		if err != nil {
+			return err
+		}
+		mode := entry.FileInfo().Mode()
+		switch {
+		case mode.IsDir():
+			err = createDir(targetDir, entryPath)
+		case mode.IsRegular():
+			err = extractZipEntry(entry, targetDir, entryPath)
+		default:
+			err = nerrors.NewInvalidArgumentError("invalid entry %q in %s: only files and directories are supported", entry.Name, source)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractZipEntry writes the content of a zip entry.
+func extractZipEntry(entry *zip.File, targetDir string, entryPath string) error {
+	reader, err := entry.Open()
+	if err != nil {
+		return nerrors.NewInvalidArgumentError("unable to read entry %q: %s", entry.Name, err.Error())
+	}
+	defer reader.Close()
+	return createFile(targetDir, entryPath, reader)
+}
+
+// checkSymlinkTarget validates that the target of a symbolic link of an archive is a relative path
+// inside the archive, as the content of any other target is not available.
+func checkSymlinkTarget(entryPath string, linkname string, source string) error {
+	slashed := strings.ReplaceAll(linkname, `\`, "/")
+	if linkname == "" || strings.HasPrefix(slashed, "/") || filepath.IsAbs(linkname) || filepath.VolumeName(linkname) != "" {
+		return nerrors.NewFailedPreconditionError("symbolic link %s in %s points to %s, outside the application directory", entryPath, source, linkname)
+	}
+	target := path.Join(path.Dir(entryPath), slashed)
+	if target == ".." || strings.HasPrefix(target, "../") {
+		return nerrors.NewFailedPreconditionError("symbolic link %s in %s points to %s, outside the application directory", entryPath, source, linkname)
+	}
+	return nil
+}
+
+// createSymlink creates a symbolic link inside the target directory creating the parent directories if required.
+func createSymlink(targetDir string, entryPath string, linkname string) error {
+	target := filepath.Join(targetDir, filepath.FromSlash(entryPath))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create directory for %s", entryPath)
+	}
+	if err := os.Symlink(filepath.FromSlash(linkname), target); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create symbolic link %s", entryPath)
+	}
+	return nil
+}
+
+// createDir creates a directory inside the target directory.
+func createDir(targetDir string, entryPath string) error {
+	if err := os.MkdirAll(filepath.Join(targetDir, filepath.FromSlash(entryPath)), 0755); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create directory %s", entryPath)
+	}
+	return nil
+}
+
+// createFile writes a file inside the target directory creating the parent directories if required.
+func createFile(targetDir string, entryPath string, content io.Reader) error {
+	target := filepath.Join(targetDir, filepath.FromSlash(entryPath))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create directory for %s", entryPath)
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create file %s", entryPath)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, content); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write file %s", entryPath)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"sort"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+// createTestArchive returns a tgz stream with the given files.
+func createTestArchive(files map[string]string) []byte {
+	var buffer bytes.Buffer
+	gw := gzip.NewWriter(&buffer)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		gomega.Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(gomega.Succeed())
+		_, err := tw.Write([]byte(content))
+		gomega.Expect(err).To(gomega.Succeed())
+	}
+	gomega.Expect(tw.Close()).To(gomega.Succeed())
+	gomega.Expect(gw.Close()).To(gomega.Succeed())
+	return buffer.Bytes()
+}
+
+var _ = ginkgo.Describe("Archive tests", func() {
+
+	var targetDir string
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-archive")
+		gomega.Expect(err).To(gomega.Succeed())
+		targetDir = dir
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(targetDir)
+	})
+
+	ginkgo.It("Should reject unsafe entry paths", func() {
+		for _, name := range []string{"/etc/passwd", "../outside", "app/../../outside", `..\outside`} {
+			_, err := sanitizeEntryPath(name)
+			gomega.Expect(err).To(gomega.HaveOccurred(), name)
+		}
+		cleaned, err := sanitizeEntryPath("./app//metadata.yaml")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(cleaned).To(gomega.Equal("app/metadata.yaml"))
+	})
+
+	ginkgo.It("Should extract a tgz with the same layout as the application directory", func() {
+		archive := createTestArchive(map[string]string{
+			"./metadata.yaml": "kind: ApplicationMetadata",
+			"./app/app.yaml":  "kind: Application",
+		})
+		files, err := extractTar(bytes.NewReader(archive), "test", targetDir, "")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(files).To(gomega.ConsistOf("metadata.yaml", "app/app.yaml"))
+
+		names, err := (&Catalog{}).readApp(targetDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		sort.Strings(names)
+		gomega.Expect(names).To(gomega.Equal([]string{"./app/app.yaml", "./metadata.yaml"}))
+	})
+
+	ginkgo.It("Should fail extracting an archive with entries outside the target directory", func() {
+		archive := createTestArchive(map[string]string{"../evil.yaml": "kind: Application"})
+		_, err := extractTar(bytes.NewReader(archive), "test", targetDir, "")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
@@ -0,0 +1,364 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/cache"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/progress"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultPullWorkers with the number of applications downloaded concurrently by a bulk pull.
+	DefaultPullWorkers = 4
+	// latestTag with the tag pulled from each application of a namespace unless all the tags are requested.
+	latestTag = "latest"
+)
+
+// BulkPullOptions with the options to pull several applications at once.
+type BulkPullOptions struct {
+	// FromFile with the path of a file with one application per line.
+	FromFile string
+	// Namespace whose applications are pulled.
+	Namespace string
+	// AllTags pulls every tag of the applications of the namespace instead of the latest one.
+	AllTags bool
+	// Workers with the maximum number of concurrent downloads.
+	Workers int
+}
+
+// IsBulk checks if several applications must be pulled.
+func (o *BulkPullOptions) IsBulk() bool {
+	return o.FromFile != "" || o.Namespace != ""
+}
+
+// bulkPullJob with an application to be downloaded.
+type bulkPullJob struct {
+	// applicationID with the application to download.
+	applicationID string
+	// ref of the application in the local cache, including the address of the catalog.
+	ref cache.Ref
+	// key of the application in the lock file.
+	key string
+	// expected digest, if any.
+	expected string
+	// result of the download.
+	result *entities.BulkPullResult
+}
+
+// PullAll downloads several applications, read from a file or listed from a namespace, writing each one
+// in <output>/<namespace>/<appName>/<tag>.tgz. The applications are downloaded concurrently using a
+// single connection per catalog.
+func (c *Catalog) PullAll(opts *PullOptions) error {
+	log.Debug().Str("fromFile", opts.FromFile).Str("namespace", opts.Namespace).Bool("allTags", opts.AllTags).Msg("PullAll received!")
+	if opts.FromFile != "" && opts.Namespace != "" {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("use either --from-file or --namespace"))
+	}
+	if opts.toDirectory() || opts.Output == StdoutPath || opts.Digest != "" {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("--extract, --format tree, --digest and --dest - are not supported when pulling several applications"))
+	}
+	outputDir := opts.Output
+	if outputDir == "" {
+		outputDir = "."
+	}
+	var lock *lockFile
+	if opts.LockFile != "" {
+		var err error
+		if lock, err = loadLockFile(opts.LockFile); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+
+	if opts.Namespace != "" && opts.Offline {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("the applications of a namespace cannot be listed in offline mode, use --from-file"))
+	}
+
+	var applicationIDs []string
+	var err error
+	if opts.FromFile != "" {
+		applicationIDs, err = readApplicationList(opts.FromFile)
+	} else {
+		applicationIDs, err = c.listNamespace(opts.Namespace, opts.AllTags)
+	}
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if len(applicationIDs) == 0 {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewNotFoundError("no applications found to pull"))
+	}
+
+	jobs := make([]*bulkPullJob, 0, len(applicationIDs))
+	for _, applicationID := range applicationIDs {
+		jobs = append(jobs, c.newBulkPullJob(applicationID, outputDir, opts.Force, lock))
+	}
+	skipDuplicateJobs(jobs)
+	connections := make(map[string]*grpc.ClientConn)
+	if !opts.Offline {
+		connections = c.connectCatalogs(jobs)
+	}
+	defer func() {
+		for _, conn := range connections {
+			conn.Close()
+		}
+	}()
+	c.runBulkPull(jobs, connections, opts)
+
+	summary := &entities.BulkPullSummary{}
+	for _, job := range jobs {
+		summary.Results = append(summary.Results, job.result)
+		if job.result.Status == entities.PullSkipped {
+			summary.NumSkipped++
+			continue
+		}
+		if job.result.Status == entities.PullFailed {
+			summary.NumFailed++
+			continue
+		}
+		summary.NumSucceeded++
+		if lock != nil && job.expected == "" {
+			lock.Applications[job.key] = job.result.Digest
+		}
+	}
+	if lock != nil && summary.NumSucceeded > 0 {
+		if err := lock.save(opts.LockFile); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+	if err := c.ResultPrinter.PrintResultOrError(summary, nil); err != nil {
+		return err
+	}
+	if summary.NumFailed > 0 {
+		return nerrors.NewInternalError("%d of %d applications could not be pulled", summary.NumFailed, len(jobs))
+	}
+	return nil
+}
+
+// readApplicationList reads a file with one application per line. Empty lines and lines starting with # are ignored.
+func readApplicationList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nerrors.NewInvalidArgumentError("unable to open %s: %s", path, err.Error())
+	}
+	defer file.Close()
+
+	var result []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result = append(result, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read %s", path)
+	}
+	return result, nil
+}
+
+// listNamespace returns the applications of a namespace, with all their tags or only the latest one.
+func (c *Catalog) listNamespace(namespace string, allTags bool) ([]string, error) {
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, fmt.Sprintf("%s/", namespace))
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s:%d",
+			c.cfg.CatalogAddress, c.cfg.CatalogPort)
+	}
+	defer conn.Close()
+
+	client := grpc_catalog_go.NewCatalogClient(conn)
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+	response, err := client.List(ctx, &grpc_catalog_go.ListApplicationsRequest{Namespace: namespace})
+	if err != nil {
+		return nil, nerrors.FromGRPC(err)
+	}
+
+	var result []string
+	for _, app := range response.Applications {
+		tags := make([]string, 0, len(app.TagMetadataName))
+		for tag := range app.TagMetadataName {
+			if allTags || tag == latestTag {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			log.Warn().Str("namespace", app.Namespace).Str("application", app.ApplicationName).Msg("application without latest tag skipped, use --all-tags to pull it")
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			result = append(result, fmt.Sprintf("%s/%s:%s", app.Namespace, app.ApplicationName, tag))
+		}
+	}
+	return result, nil
+}
+
+// newBulkPullJob prepares the download of an application. Jobs that cannot be downloaded are
+// returned with a failed result.
+func (c *Catalog) newBulkPullJob(applicationID string, outputDir string, force bool, lock *lockFile) *bulkPullJob {
+	job := &bulkPullJob{
+		applicationID: applicationID,
+		result:        &entities.BulkPullResult{ApplicationID: applicationID, Status: entities.PullSuccess},
+	}
+	fail := func(err error) *bulkPullJob {
+		job.result.Status = entities.PullFailed
+		job.result.Info = err.Error()
+		return job
+	}
+
+	_, namespace, appName, tag, err := DecomposeApplicationName(applicationID)
+	if err != nil {
+		return fail(err)
+	}
+	for _, name := range []string{namespace, appName, tag} {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fail(nerrors.NewInvalidArgumentError("invalid application name %q", applicationID))
+		}
+	}
+	job.result.Path = filepath.Join(outputDir, namespace, appName, tag+".tgz")
+	if err := checkOverwrite(job.result.Path, force); err != nil {
+		return fail(err)
+	}
+	if job.ref, err = c.cacheRef(applicationID); err != nil {
+		return fail(err)
+	}
+	if job.key, err = lockKey(applicationID); err != nil {
+		return fail(err)
+	}
+	if job.expected, err = expectedDigest(job.key, &PullOptions{}, lock); err != nil {
+		return fail(err)
+	}
+	return job
+}
+
+// skipDuplicateJobs ensures that each output path is written by a single job. Jobs pulling an application
+// already pulled by a previous job are skipped, while jobs pulling a different application into the
+// same path, such as the same tag of two catalogs, fail as the files would overwrite each other.
+func skipDuplicateJobs(jobs []*bulkPullJob) {
+	byPath := make(map[string]*bulkPullJob)
+	for _, job := range jobs {
+		if job.result.Status != entities.PullSuccess {
+			continue
+		}
+		previous, exists := byPath[job.result.Path]
+		if !exists {
+			byPath[job.result.Path] = job
+			continue
+		}
+		if previous.ref == job.ref {
+			log.Debug().Str("applicationID", job.applicationID).Str("duplicateOf", previous.applicationID).Msg("duplicated application skipped")
+			job.result.Status = entities.PullSkipped
+			job.result.Info = fmt.Sprintf("duplicate of %s", previous.applicationID)
+			continue
+		}
+		job.result.Status = entities.PullFailed
+		job.result.Info = fmt.Sprintf("%s is also written by %s", job.result.Path, previous.applicationID)
+	}
+}
+
+// connectCatalogs opens a connection with each catalog used by the jobs. The jobs of catalogs that
+// cannot be reached are marked as failed.
+func (c *Catalog) connectCatalogs(jobs []*bulkPullJob) map[string]*grpc.ClientConn {
+	connections := make(map[string]*grpc.ClientConn)
+	failed := make(map[string]error)
+	for _, job := range jobs {
+		if job.result.Status != entities.PullSuccess {
+			continue
+		}
+		if _, exists := connections[job.ref.CatalogURL]; !exists && failed[job.ref.CatalogURL] == nil {
+			conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, job.applicationID)
+			if err != nil {
+				failed[job.ref.CatalogURL] = nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s", job.ref.CatalogURL)
+			} else {
+				connections[job.ref.CatalogURL] = conn
+			}
+		}
+		if err := failed[job.ref.CatalogURL]; err != nil {
+			job.result.Status = entities.PullFailed
+			job.result.Info = err.Error()
+		}
+	}
+	return connections
+}
+
+// runBulkPull downloads the applications using a bounded number of workers.
+func (c *Catalog) runBulkPull(jobs []*bulkPullJob, connections map[string]*grpc.ClientConn, opts *PullOptions) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultPullWorkers
+	}
+	tracker := progress.NewTracker("pull", fmt.Sprintf("%d applications", len(jobs)), len(jobs), 0)
+	defer tracker.Done()
+
+	pending := make(chan *bulkPullJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range pending {
+				size, err := c.pullBulkJob(job, connections[job.ref.CatalogURL], &opts.CacheOptions)
+				if err != nil {
+					log.Debug().Err(err).Str("applicationID", job.applicationID).Msg("pull failed")
+					job.result.Status = entities.PullFailed
+					job.result.Info = err.Error()
+				}
+				tracker.Add(1, size)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		if job.result.Status == entities.PullSuccess {
+			pending <- job
+		}
+	}
+	close(pending)
+	wg.Wait()
+}
+
+// pullBulkJob reads an application from the local cache or downloads it, verifying its digest, if
+// expected, before writing it.
+func (c *Catalog) pullBulkJob(job *bulkPullJob, conn *grpc.ClientConn, opts *CacheOptions) (int64, error) {
+	bundle, err := c.cachedBundle(job.ref, job.expected, opts)
+	if err != nil {
+		return 0, err
+	}
+	if bundle == nil {
+		if bundle, err = c.downloadBundle(grpc_catalog_go.NewCatalogClient(conn), job.applicationID, false, nil); err != nil {
+			return 0, err
+		}
+		c.storeBundle(job.ref, bundle)
+	}
+	size := int64(len(bundle.archive.Data))
+	if job.expected != "" && !strings.EqualFold(job.expected, bundle.digest) {
+		return size, nerrors.NewFailedPreconditionError("digest mismatch, expected %s but received %s", job.expected, bundle.digest)
+	}
+	job.result.Digest = bundle.digest
+	return size, saveApplication(bundle.archive, job.result.Path)
+}
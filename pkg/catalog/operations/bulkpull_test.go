@@ -0,0 +1,96 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/napptive/catalog-cli/v2/pkg/config"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Bulk pull tests", func() {
+
+	var outputDir string
+	var catalog *Catalog
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-bulk")
+		gomega.Expect(err).To(gomega.Succeed())
+		outputDir = dir
+		catalog = &Catalog{cfg: &config.Config{ConnectionConfig: config.ConnectionConfig{CatalogAddress: "catalog", CatalogPort: 7060}}}
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(outputDir)
+	})
+
+	ginkgo.It("Should read the list of applications ignoring comments and empty lines", func() {
+		path := filepath.Join(outputDir, "apps.txt")
+		gomega.Expect(os.WriteFile(path, []byte("# snapshot\nns/app:1.0\n\n  other/app  \n"), 0644)).To(gomega.Succeed())
+		applications, err := readApplicationList(path)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(applications).To(gomega.Equal([]string{"ns/app:1.0", "other/app"}))
+	})
+
+	ginkgo.It("Should write each application in its namespace and application directory", func() {
+		job := catalog.newBulkPullJob("ns/app", outputDir, false, nil)
+		gomega.Expect(job.result.Status).To(gomega.Equal(entities.PullSuccess))
+		gomega.Expect(job.result.Path).To(gomega.Equal(filepath.Join(outputDir, "ns", "app", "latest.tgz")))
+		gomega.Expect(job.ref.CatalogURL).To(gomega.Equal("catalog:7060"))
+
+		job = catalog.newBulkPullJob("other.catalog/ns/app:1.0", outputDir, false, nil)
+		gomega.Expect(job.result.Path).To(gomega.Equal(filepath.Join(outputDir, "ns", "app", "1.0.tgz")))
+		gomega.Expect(job.ref.CatalogURL).To(gomega.Equal("other.catalog:7060"))
+	})
+
+	ginkgo.It("Should fail the applications that cannot be written", func() {
+		gomega.Expect(catalog.newBulkPullJob("ns/..:1.0", outputDir, false, nil).result.Status).To(gomega.Equal(entities.PullFailed))
+		gomega.Expect(catalog.newBulkPullJob("app", outputDir, false, nil).result.Status).To(gomega.Equal(entities.PullFailed))
+
+		existing := filepath.Join(outputDir, "ns", "app", "1.0.tgz")
+		gomega.Expect(os.MkdirAll(filepath.Dir(existing), 0755)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(existing, []byte("tgz"), 0644)).To(gomega.Succeed())
+		gomega.Expect(catalog.newBulkPullJob("ns/app:1.0", outputDir, false, nil).result.Status).To(gomega.Equal(entities.PullFailed))
+		gomega.Expect(catalog.newBulkPullJob("ns/app:1.0", outputDir, true, nil).result.Status).To(gomega.Equal(entities.PullSuccess))
+	})
+	ginkgo.It("Should write each output path once", func() {
+		jobs := []*bulkPullJob{
+			catalog.newBulkPullJob("ns/app", outputDir, false, nil),
+			catalog.newBulkPullJob("ns/app:1.0", outputDir, false, nil),
+			catalog.newBulkPullJob("ns/app:latest", outputDir, false, nil),
+			catalog.newBulkPullJob("catalog:7060/ns/app:1.0", outputDir, false, nil),
+			catalog.newBulkPullJob("other.catalog/ns/app:1.0", outputDir, false, nil),
+			catalog.newBulkPullJob("app", outputDir, false, nil),
+		}
+		skipDuplicateJobs(jobs)
+		statuses := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			statuses = append(statuses, job.result.Status)
+		}
+		gomega.Expect(statuses).To(gomega.Equal([]string{
+			entities.PullSuccess, entities.PullSuccess, entities.PullSkipped,
+			entities.PullSkipped, entities.PullFailed, entities.PullFailed,
+		}))
+		gomega.Expect(jobs[2].result.Info).To(gomega.Equal("duplicate of ns/app"))
+		gomega.Expect(jobs[3].result.Info).To(gomega.Equal("duplicate of ns/app:1.0"))
+		gomega.Expect(jobs[4].result.Info).To(gomega.ContainSubstring("is also written by ns/app:1.0"))
+	})
+})
@@ -0,0 +1,211 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/progress"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/prompt"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultRemoveWorkers with the number of applications removed concurrently by a bulk remove.
+const DefaultRemoveWorkers = 4
+
+// BulkRemoveOptions with the options to remove several applications at once.
+type BulkRemoveOptions struct {
+	// Namespace whose applications are removed.
+	Namespace string
+	// Match with the pattern of the application tags to remove: appName[:tag].
+	Match string
+	// Workers with the maximum number of concurrent removals.
+	Workers int
+}
+
+// IsBulk checks if several applications must be removed.
+func (o *BulkRemoveOptions) IsBulk() bool {
+	return o.Namespace != ""
+}
+
+// matchPatterns returns the patterns of the application name and the tag. All the tags match if
+// the pattern does not contain a tag.
+func (o *BulkRemoveOptions) matchPatterns() (string, string, error) {
+	appPattern, tagPattern := o.Match, "*"
+	if index := strings.Index(o.Match, ":"); index != -1 {
+		appPattern, tagPattern = o.Match[:index], o.Match[index+1:]
+	}
+	for _, pattern := range []string{appPattern, tagPattern} {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return "", "", nerrors.NewInvalidArgumentError("invalid pattern %q, expecting appName[:tag] with * and ? wildcards", o.Match)
+		}
+	}
+	return appPattern, tagPattern, nil
+}
+
+// RemoveAll deletes the application tags of a namespace matching a pattern. The list of application
+// tags is always shown and the operation must be confirmed unless the Yes option is set. The
+// applications are removed concurrently.
+func (c *Catalog) RemoveAll(opts *RemoveOptions) error {
+	log.Debug().Str("namespace", opts.Namespace).Str("match", opts.Match).Bool("dryRun", opts.DryRun).Msg("RemoveAll received!")
+	if opts.Match == "" {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("--match is required to remove the applications of a namespace"))
+	}
+	appPattern, tagPattern, err := opts.matchPatterns()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	catalogURL, err := connection.GetURL(&c.cfg.ConnectionConfig, fmt.Sprintf("%s/", opts.Namespace))
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+
+	// Connection
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, fmt.Sprintf("%s/", opts.Namespace))
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s:%d",
+			c.cfg.CatalogAddress, c.cfg.CatalogPort))
+	}
+	defer conn.Close()
+	client := grpc_catalog_go.NewCatalogClient(conn)
+
+	ctx, cancel := c.AuthToken.GetContext()
+	response, err := client.List(ctx, &grpc_catalog_go.ListApplicationsRequest{Namespace: opts.Namespace})
+	cancel()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.FromGRPC(err))
+	}
+	plan := &entities.RemovePlan{CatalogURL: catalogURL, Targets: matchingTags(response.Applications, opts.Namespace, appPattern, tagPattern)}
+	if len(plan.Targets) == 0 {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewNotFoundError("no applications of %s match %s", opts.Namespace, opts.Match))
+	}
+	if err := c.ResultPrinter.PrintResultOrError(plan, nil); err != nil {
+		return err
+	}
+	if opts.DryRun {
+		return nil
+	}
+	if !opts.Yes {
+		if !prompt.IsInteractive() {
+			return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewFailedPreconditionError("use --yes to remove %d applications without confirmation", len(plan.Targets)))
+		}
+		confirmed, err := prompt.Confirm(os.Stdin, os.Stderr, fmt.Sprintf("Remove %d application tags from %s?", len(plan.Targets), catalogURL))
+		if err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+		if !confirmed {
+			return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewCanceledError("remove canceled, no applications have been removed"))
+		}
+	}
+
+	if opts.Backup {
+		if _, err := c.backupApplications(client, removeOperation, catalogURL, plan.Targets); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+
+	summary := c.runBulkRemove(client, plan.Targets, opts.Workers)
+	if err := c.ResultPrinter.PrintResultOrError(summary, nil); err != nil {
+		return err
+	}
+	if summary.NumFailed > 0 {
+		return nerrors.NewInternalError("%d of %d applications could not be removed", summary.NumFailed, len(plan.Targets))
+	}
+	return nil
+}
+
+// matchingTags returns the application tags of a namespace whose name and tag match the patterns.
+func matchingTags(applications []*grpc_catalog_go.ApplicationSummary, namespace string, appPattern string, tagPattern string) []*entities.RemoveTarget {
+	result := make([]*entities.RemoveTarget, 0)
+	for _, app := range applications {
+		if app.Namespace != namespace {
+			continue
+		}
+		if matched, _ := path.Match(appPattern, app.ApplicationName); !matched {
+			continue
+		}
+		for tag, name := range app.TagMetadataName {
+			if matched, _ := path.Match(tagPattern, tag); matched {
+				result = append(result, &entities.RemoveTarget{
+					ApplicationID: fmt.Sprintf("%s/%s:%s", app.Namespace, app.ApplicationName, tag),
+					Name:          name,
+					Private:       app.Private,
+				})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ApplicationID < result[j].ApplicationID
+	})
+	return result
+}
+
+// runBulkRemove removes the application tags using a pool of workers.
+func (c *Catalog) runBulkRemove(client grpc_catalog_go.CatalogClient, targets []*entities.RemoveTarget, workers int) *entities.BulkRemoveSummary {
+	if workers <= 0 {
+		workers = DefaultRemoveWorkers
+	}
+	tracker := progress.NewTracker("remove", fmt.Sprintf("%d applications", len(targets)), len(targets), 0)
+	defer tracker.Done()
+
+	summary := &entities.BulkRemoveSummary{Results: make([]*entities.BulkRemoveResult, len(targets))}
+	pending := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range pending {
+				applicationID := targets[index].ApplicationID
+				result := &entities.BulkRemoveResult{ApplicationID: applicationID, Status: entities.RemoveSuccess}
+				ctx, cancel := c.AuthToken.GetContext()
+				_, err := client.Remove(ctx, &grpc_catalog_go.RemoveApplicationRequest{ApplicationId: applicationID})
+				cancel()
+				if err != nil {
+					log.Debug().Err(err).Str("applicationID", applicationID).Msg("remove failed")
+					result.Status = entities.RemoveFailed
+					result.Info = nerrors.FromGRPC(err).Error()
+				}
+				summary.Results[index] = result
+				tracker.Add(1, 0)
+			}
+		}()
+	}
+	for index := range targets {
+		pending <- index
+	}
+	close(pending)
+	wg.Wait()
+
+	for _, result := range summary.Results {
+		if result.Status == entities.RemoveFailed {
+			summary.NumFailed++
+		} else {
+			summary.NumSucceeded++
+		}
+	}
+	return summary
+}
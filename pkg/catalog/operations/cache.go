@@ -0,0 +1,260 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"strings"
+	"time"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/cache"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/progress"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// CacheOptions with the options that control the use of the local cache.
+type CacheOptions struct {
+	// Offline serves the applications from the local cache without contacting the catalog.
+	Offline bool
+	// Refresh downloads the applications from the catalog even if they are in the local cache.
+	Refresh bool
+}
+
+// appBundle with the content of an application, as a tgz file and, if downloaded uncompressed, as a list of files.
+type appBundle struct {
+	// archive with the tgz file.
+	archive *grpc_catalog_go.FileInfo
+	// files of the application.
+	files []*grpc_catalog_go.FileInfo
+	// digest of the application content.
+	digest string
+	// cached is set if the bundle has been read from the local cache.
+	cached bool
+}
+
+// tgz returns the application as a tgz file.
+func (b *appBundle) tgz(name string) (*grpc_catalog_go.FileInfo, error) {
+	if b.archive == nil {
+		data, err := createArchive(b.files)
+		if err != nil {
+			return nil, err
+		}
+		b.archive = &grpc_catalog_go.FileInfo{Path: name, Data: data}
+	}
+	return b.archive, nil
+}
+
+// entries returns the files of the application.
+func (b *appBundle) entries() ([]*grpc_catalog_go.FileInfo, error) {
+	if b.files == nil {
+		files, err := archiveFiles(b.archive.Data, b.archive.Path)
+		if err != nil {
+			return nil, err
+		}
+		b.files = files
+	}
+	return b.files, nil
+}
+
+// localCache returns the cache of applications.
+func (c *Catalog) localCache() (*cache.Cache, error) {
+	if c.cfg.CacheDir != "" {
+		return cache.New(c.cfg.CacheDir), nil
+	}
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(dir), nil
+}
+
+// cacheRef returns the reference of an application in the cache.
+func (c *Catalog) cacheRef(applicationID string) (cache.Ref, error) {
+	catalogURL, err := connection.GetURL(&c.cfg.ConnectionConfig, applicationID)
+	if err != nil {
+		return cache.Ref{}, err
+	}
+	_, namespace, appName, tag, err := DecomposeApplicationName(applicationID)
+	if err != nil {
+		return cache.Ref{}, err
+	}
+	return cache.Ref{CatalogURL: catalogURL, Namespace: namespace, ApplicationName: appName, Tag: tag}, nil
+}
+
+// cachedBundle returns the application from the local cache, or nil if it must be downloaded. The
+// latest tag is expected to change so it is only served from the cache in offline mode or if its
+// digest is the expected one, while other tags are considered immutable.
+func (c *Catalog) cachedBundle(ref cache.Ref, expected string, opts *CacheOptions) (*appBundle, error) {
+	if opts.Refresh && !opts.Offline {
+		return nil, nil
+	}
+	miss := func(reason string) (*appBundle, error) {
+		if opts.Offline {
+			return nil, nerrors.NewNotFoundError("%s %s, it cannot be pulled in offline mode", ref.String(), reason)
+		}
+		log.Debug().Str("ref", ref.String()).Str("reason", reason).Msg("cache miss")
+		return nil, nil
+	}
+	localCache, err := c.localCache()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := localCache.Get(ref)
+	if err != nil {
+		return miss(err.Error())
+	}
+	if entry == nil || entry.Digest == "" {
+		return miss("is not in the local cache")
+	}
+	if !opts.Offline {
+		if expected != "" && !strings.EqualFold(expected, entry.Digest) {
+			return miss("has a different digest in the local cache")
+		}
+		if expected == "" && ref.Tag == latestTag {
+			return miss("uses the latest tag")
+		}
+	}
+	data, err := localCache.ReadBundle(ref, entry)
+	if err != nil {
+		return miss(err.Error())
+	}
+	archive := &grpc_catalog_go.FileInfo{Path: ref.ApplicationName + ".tgz", Data: data}
+	digest, err := archiveDigest(archive.Data, archive.Path)
+	if err != nil || digest != entry.Digest {
+		log.Warn().Str("ref", ref.String()).Msg("corrupted bundle in the local cache")
+		return miss("is corrupted in the local cache")
+	}
+	log.Debug().Str("ref", ref.String()).Str("digest", digest).Msg("application read from the local cache")
+	return &appBundle{archive: archive, digest: digest, cached: true}, nil
+}
+
+// storeBundle adds a downloaded application to the local cache. Failures are only logged as the
+// application has already been downloaded.
+func (c *Catalog) storeBundle(ref cache.Ref, bundle *appBundle) {
+	archive, err := bundle.tgz(ref.ApplicationName + ".tgz")
+	if err == nil {
+		var localCache *cache.Cache
+		if localCache, err = c.localCache(); err == nil {
+			err = localCache.PutBundle(ref, bundle.digest, archive.Data)
+		}
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("ref", ref.String()).Msg("unable to store the application in the local cache")
+	}
+}
+
+// cachedInfo returns the information of an application stored in the local cache.
+func (c *Catalog) cachedInfo(ref cache.Ref) (*grpc_catalog_go.InfoApplicationResponse, error) {
+	localCache, err := c.localCache()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := localCache.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.Info == nil {
+		return nil, nerrors.NewNotFoundError("the information of %s is not in the local cache, it cannot be read in offline mode", ref.String())
+	}
+	return entry.Info, nil
+}
+
+// storeInfo adds the information of an application to the local cache. Failures are only logged.
+func (c *Catalog) storeInfo(ref cache.Ref, info *grpc_catalog_go.InfoApplicationResponse) {
+	localCache, err := c.localCache()
+	if err == nil {
+		err = localCache.PutInfo(ref, info)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("ref", ref.String()).Msg("unable to store the application information in the local cache")
+	}
+}
+
+// knownDigest returns the digest of the content of an application tag according to the local cache,
+// or an empty string if it is unknown.
+func (c *Catalog) knownDigest(ref cache.Ref) string {
+	localCache, err := c.localCache()
+	if err != nil {
+		return ""
+	}
+	entry, err := localCache.Get(ref)
+	if err != nil || entry == nil {
+		return ""
+	}
+	if entry.Digest != "" {
+		return entry.Digest
+	}
+	return entry.PushedDigest
+}
+
+// storePushedDigest records the digest of a pushed application in the local cache. Failures are only logged.
+func (c *Catalog) storePushedDigest(ref cache.Ref, digest string) {
+	localCache, err := c.localCache()
+	if err == nil {
+		err = localCache.PutPushedDigest(ref, digest)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("ref", ref.String()).Msg("unable to store the pushed digest in the local cache")
+	}
+}
+
+// downloadBundle downloads an application, uncompressed if tree is set, computing its digest.
+func (c *Catalog) downloadBundle(client grpc_catalog_go.CatalogClient, applicationID string, tree bool, tracker *progress.Tracker) (*appBundle, error) {
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+	files, err := c.downloadApplication(ctx, client, applicationID, !tree, tracker)
+	if err != nil {
+		return nil, err
+	}
+	if tree {
+		return &appBundle{files: files, digest: filesDigest(files)}, nil
+	}
+	digest, err := archiveDigest(files[0].Data, files[0].Path)
+	if err != nil {
+		return nil, err
+	}
+	return &appBundle{archive: files[0], digest: digest}, nil
+}
+
+// CacheList prints the content of the local cache.
+func (c *Catalog) CacheList() error {
+	localCache, err := c.localCache()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	return c.ResultPrinter.PrintResultOrError(localCache.List())
+}
+
+// CachePrune removes from the local cache the applications not used in the given duration.
+func (c *Catalog) CachePrune(maxAge time.Duration) error {
+	localCache, err := c.localCache()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	return c.ResultPrinter.PrintResultOrError(localCache.Prune(maxAge))
+}
+
+// CacheClear removes all the content of the local cache.
+func (c *Catalog) CacheClear() error {
+	localCache, err := c.localCache()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	return c.ResultPrinter.PrintResultOrError(localCache.Clear())
+}
@@ -0,0 +1,95 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+
+	"github.com/napptive/catalog-cli/v2/pkg/config"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Cache tests", func() {
+
+	var cacheDir string
+	var catalog *Catalog
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-cache")
+		gomega.Expect(err).To(gomega.Succeed())
+		cacheDir = dir
+		catalog = &Catalog{cfg: &config.Config{
+			ConnectionConfig: config.ConnectionConfig{CatalogAddress: "catalog", CatalogPort: 7060},
+			CacheDir:         cacheDir,
+		}}
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(cacheDir)
+	})
+
+	storeTestBundle := func(applicationID string) string {
+		ref, err := catalog.cacheRef(applicationID)
+		gomega.Expect(err).To(gomega.Succeed())
+		data := createTestArchive(map[string]string{"./metadata.yaml": "kind: ApplicationMetadata"})
+		digest, err := archiveDigest(data, "app.tgz")
+		gomega.Expect(err).To(gomega.Succeed())
+		catalog.storeBundle(ref, &appBundle{archive: &grpc_catalog_go.FileInfo{Path: "app.tgz", Data: data}, digest: digest})
+		return digest
+	}
+
+	ginkgo.It("Should fail clearly on a miss in offline mode", func() {
+		ref, err := catalog.cacheRef("ns/app:1.0")
+		gomega.Expect(err).To(gomega.Succeed())
+		bundle, err := catalog.cachedBundle(ref, "", &CacheOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(bundle).To(gomega.BeNil())
+
+		_, err = catalog.cachedBundle(ref, "", &CacheOptions{Offline: true})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(nerrors.FromError(err).Code).To(gomega.Equal(nerrors.NotFound))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("offline"))
+	})
+
+	ginkgo.It("Should only serve the latest tag if offline or if the digest is the expected one", func() {
+		fixed := storeTestBundle("ns/app:1.0")
+		latest := storeTestBundle("ns/app")
+		gomega.Expect(fixed).To(gomega.Equal(latest))
+
+		ref, _ := catalog.cacheRef("ns/app:1.0")
+		bundle, err := catalog.cachedBundle(ref, "", &CacheOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(bundle.cached).To(gomega.BeTrue())
+		bundle, err = catalog.cachedBundle(ref, "", &CacheOptions{Refresh: true})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(bundle).To(gomega.BeNil())
+
+		ref, _ = catalog.cacheRef("ns/app")
+		bundle, err = catalog.cachedBundle(ref, "", &CacheOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(bundle).To(gomega.BeNil())
+		bundle, err = catalog.cachedBundle(ref, latest, &CacheOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(bundle.digest).To(gomega.Equal(latest))
+		bundle, err = catalog.cachedBundle(ref, "", &CacheOptions{Offline: true})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(bundle.digest).To(gomega.Equal(latest))
+	})
+})
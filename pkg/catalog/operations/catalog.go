@@ -17,6 +17,7 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -24,11 +25,11 @@ import (
 
 	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
 	"github.com/napptive/catalog-cli/v2/internal/pkg/printer"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/progress"
 	"github.com/napptive/catalog-cli/v2/pkg/config"
 	grpc_catalog_common_go "github.com/napptive/grpc-catalog-common-go"
 	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
 	"github.com/napptive/nerrors/pkg/nerrors"
-	"github.com/rs/zerolog/log"
 )
 
 type Catalog struct {
@@ -52,8 +53,10 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 	}, nil
 }
 
-// loadApp reads the application directory getting all the files and their paths
-func (c *Catalog) loadApp(path string, relativePath string) ([]string, error) {
+// loadApp reads the application directory getting all the files and their paths. Files and
+// directories matching the ignore rules are excluded and symbolic links are handled following
+// the policy of the walker.
+func (c *Catalog) loadApp(path string, relativePath string, walker *appWalker) ([]string, error) {
 	dir, err := os.Open(path)
 	if err != nil {
 		return nil, nerrors.NewInvalidArgumentError("unable to open directory %s. Check that the path is correct, it is accessible by the current user, and it contains an application file", path)
@@ -61,6 +64,12 @@ func (c *Catalog) loadApp(path string, relativePath string) ([]string, error) {
 
 	defer dir.Close()
 
+	realPath, err := walker.enter(path, relativePath)
+	if err != nil {
+		return nil, err
+	}
+	defer walker.leave(realPath)
+
 	var result []string
 	directories, err := dir.Readdirnames(0)
 	if err != nil {
@@ -68,101 +77,46 @@ func (c *Catalog) loadApp(path string, relativePath string) ([]string, error) {
 	}
 	for _, dirName := range directories {
 		newPath := fmt.Sprintf("%s/%s", path, dirName)
-		file, err := os.Stat(newPath)
+		newRelativePath := fmt.Sprintf("%s/%s", relativePath, dirName)
+		info, err := os.Lstat(newPath)
+		if err != nil {
+			return nil, err
+		}
+		if walker.ignored(newRelativePath, info.IsDir()) {
+			continue
+		}
+		file, err := walker.resolve(newPath, newRelativePath, info)
 		if err != nil {
 			return nil, err
 		}
+		// Links to directories are checked again as rules may only apply to directories
+		if file == nil || (file.IsDir() && !info.IsDir() && walker.ignored(newRelativePath, true)) {
+			continue
+		}
 		if file.IsDir() {
-			res, nErr := c.loadApp(newPath, fmt.Sprintf("%s/%s", relativePath, dirName))
+			res, nErr := c.loadApp(newPath, newRelativePath, walker)
 			if nErr != nil {
 				return nil, nErr
 			}
 			result = append(result, res...)
 
 		} else {
-			result = append(result, fmt.Sprintf("%s/%s", relativePath, dirName))
+			result = append(result, newRelativePath)
 		}
 	}
 
 	return result, nil
 }
 
-// Push adds a new application to catalog
-func (c *Catalog) Push(applicationID string, path string, privateApp bool) error {
-	log.Debug().Str("applicationID", applicationID).Str("path", path).Msg("Push received!")
-
-	// Read the path and compose the AddCatalogRequest
-	names, err := c.loadApp(path, ".")
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
-	}
-	log.Debug().Interface("names", names).Msg("Files found")
-
-	// Send the request
-	// Read the paths and compose the AddCatalogRequest
-	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, applicationID)
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
-	}
-	defer conn.Close()
-
-	client := grpc_catalog_go.NewCatalogClient(conn)
-	ctx, cancel := c.AuthToken.GetContext()
-	defer cancel()
-
-	// Get response and print result
-	stream, err := client.Add(ctx)
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
-	}
-	for _, fileName := range names {
-		readPath := fmt.Sprintf("%s/%s", path, fileName)
-		data, err := os.ReadFile(readPath)
-		if err != nil {
-			return c.ResultPrinter.PrintResultOrError(nil, err)
-		}
-		if err := stream.Send(&grpc_catalog_go.AddApplicationRequest{
-			ApplicationId: applicationID,
-			Private:       privateApp,
-			File: &grpc_catalog_go.FileInfo{
-				Path: fileName,
-				Data: data,
-			},
-		}); err != nil {
-			return c.ResultPrinter.PrintResultOrError(nil, err)
-		}
-	}
-
-	reply, err := stream.CloseAndRecv()
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
-	}
-	log.Debug().Interface("reply", reply).Msg("Application sent")
-
-	return c.ResultPrinter.PrintResultOrError(reply, nil)
-}
-
-// Pull downloads application files
-func (c *Catalog) Pull(applicationID string) error {
-
-	// Connection
-	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, applicationID)
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
-	}
-	defer conn.Close()
-
-	// Client
-	client := grpc_catalog_go.NewCatalogClient(conn)
-	ctx, cancel := c.AuthToken.GetContext()
-	defer cancel()
-
-	// Call Download
+// downloadApplication receives the files of an application. If compressed is set, the catalog
+// returns a single tgz file. The progress is reported to the tracker, if any.
+func (c *Catalog) downloadApplication(ctx context.Context, client grpc_catalog_go.CatalogClient, applicationID string, compressed bool, tracker *progress.Tracker) ([]*grpc_catalog_go.FileInfo, error) {
+	defer tracker.Done()
 	downClient, err := client.Download(ctx, &grpc_catalog_go.DownloadApplicationRequest{
-		ApplicationId: applicationID, Compressed: true,
+		ApplicationId: applicationID, Compressed: compressed,
 	})
 	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
+		return nil, err
 	}
 
 	// Receive data
@@ -174,69 +128,15 @@ func (c *Catalog) Pull(applicationID string) error {
 			break
 		}
 		if err != nil {
-			return c.ResultPrinter.PrintResultOrError(nil, err)
+			return nil, err
 		}
 		files = append(files, fileReceived)
+		tracker.Add(1, int64(len(fileReceived.Data)))
 	}
-
-	// Get the application name
-	_, _, appName, _, err := DecomposeApplicationName(applicationID)
-	if err != nil {
-		// It should not fail, in this case, the file will be named "application.tgz
-		appName = "application"
-	}
-
-	// Save the files in a tgz file
-	err = SaveFile(appName, files[0])
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
+	if len(files) == 0 {
+		return nil, nerrors.NewNotFoundError("no files received for application %s", applicationID)
 	}
-	return c.ResultPrinter.PrintResultOrError(&grpc_catalog_common_go.OpResponse{
-		Status:     grpc_catalog_common_go.OpStatus_SUCCESS,
-		StatusName: grpc_catalog_common_go.OpStatus_SUCCESS.String(),
-		UserInfo:   fmt.Sprintf("application saved on %s", files[0].Path),
-	}, nil)
-
-}
-
-// Remove deletes an application from catalog repository
-func (c *Catalog) Remove(applicationID string) error {
-
-	// Connection
-	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, applicationID)
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s:%d",
-			c.cfg.CatalogAddress, c.cfg.CatalogPort))
-	}
-	defer conn.Close()
-
-	// Client
-	client := grpc_catalog_go.NewCatalogClient(conn)
-	ctx, cancel := c.AuthToken.GetContext()
-	defer cancel()
-
-	// Call Delete op
-	response, err := client.Remove(ctx, &grpc_catalog_go.RemoveApplicationRequest{ApplicationId: applicationID})
-	return c.ResultPrinter.PrintResultOrError(response, err)
-}
-
-// Info gets application information
-func (c *Catalog) Info(application string) error {
-	// Connection
-	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, application)
-	if err != nil {
-		return c.ResultPrinter.PrintResultOrError(nil, err)
-	}
-	defer conn.Close()
-
-	// Client
-	client := grpc_catalog_go.NewCatalogClient(conn)
-	ctx, cancel := c.AuthToken.GetContext()
-	defer cancel()
-
-	// Call Delete op
-	response, err := client.Info(ctx, &grpc_catalog_go.InfoApplicationRequest{ApplicationId: application})
-	return c.ResultPrinter.PrintResultOrError(response, err)
+	return files, nil
 }
 
 // List returns the applications
@@ -323,8 +223,9 @@ func (c *Catalog) splitApplicationName(applicationName string) (string, string,
 
 }
 
-// ChangeVisibility changes the visibility of an application (for all tags)
-func (c *Catalog) ChangeVisibility(applicationName string, isPrivate bool, isPublic bool) error {
+// ChangeVisibility changes the visibility of an application (for all tags). With the Backup option,
+// all the tags are saved in the trash before changing it.
+func (c *Catalog) ChangeVisibility(applicationName string, isPrivate bool, isPublic bool, opts *BackupOptions) error {
 
 	// validate
 	if isPrivate == isPublic {
@@ -348,6 +249,17 @@ func (c *Catalog) ChangeVisibility(applicationName string, isPrivate bool, isPub
 
 	// Client
 	client := grpc_catalog_go.NewCatalogClient(conn)
+
+	if opts.Backup {
+		plan, err := c.removePlan(client, fmt.Sprintf("%s/%s", namespace, app))
+		if err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+		if _, err := c.backupApplications(client, visibilityOperation, plan.CatalogURL, plan.Targets); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+
 	ctx, cancel := c.AuthToken.GetContext()
 	defer cancel()
 
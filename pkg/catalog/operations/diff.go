@@ -0,0 +1,343 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/diff"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// DiffOptions with the options of the diff operation.
+type DiffOptions struct {
+	WalkOptions
+	// Stat only reports the number of changed lines of each file, without the patches.
+	Stat bool
+	// Context with the number of unchanged lines shown around each change.
+	Context int
+}
+
+// DifferencesFoundError is returned by Diff once the result is printed if the applications are different.
+type DifferencesFoundError struct {
+	// NumFiles with the number of files that are different.
+	NumFiles int
+}
+
+// Error returns the description of the error.
+func (e *DifferencesFoundError) Error() string {
+	return fmt.Sprintf("%d files are different", e.NumFiles)
+}
+
+// Diff compares an application stored in the catalog with another version of the application. The
+// target may be a local application, read with the same rules used by push so that the result shows
+// the changes that pushing it would introduce, or another application of the same or a different
+// catalog. A DifferencesFoundError is returned if any file is different.
+func (c *Catalog) Diff(applicationID string, target string, opts *DiffOptions) error {
+	log.Debug().Str("applicationID", applicationID).Str("target", target).Msg("Diff received!")
+	local, err := isLocalApplication(target)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	fromFiles, err := c.remoteFiles(applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	var toFiles []*grpc_catalog_go.FileInfo
+	if local {
+		toFiles, err = c.localFiles(target, opts.WalkOptions)
+	} else {
+		toFiles, err = c.remoteFiles(target)
+	}
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+
+	result := compareFiles(fromFiles, toFiles, opts)
+	result.From = applicationID
+	result.To = target
+	if err := c.ResultPrinter.PrintResultOrError(result, nil); err != nil {
+		return err
+	}
+	if result.HasChanges() {
+		return &DifferencesFoundError{NumFiles: len(result.Files)}
+	}
+	return nil
+}
+
+// isLocalApplication checks if the target of a diff is a local directory or archive instead of an application
+// of the catalog. The target is an application of the catalog only if it does not exist locally and it is a
+// valid application name, otherwise the missing path is reported.
+func isLocalApplication(target string) (bool, error) {
+	if target == StdinPath {
+		return true, nil
+	}
+	_, err := os.Stat(target)
+	if err == nil {
+		return true, nil
+	}
+	if !os.IsNotExist(err) {
+		return false, nerrors.NewInvalidArgumentError("unable to read %s: %s", target, err.Error())
+	}
+	if !isApplicationName(target) {
+		return false, nerrors.NewNotFoundError("%s does not exist and it is not an application of the catalog, [catalogURL/]namespace/appName[:tag]", target)
+	}
+	return false, nil
+}
+
+// isApplicationName checks if a target can be an application of the catalog. Paths starting with ., / or ~,
+// with empty or relative components, or with an archive extension are rejected.
+func isApplicationName(target string) bool {
+	if strings.HasPrefix(target, "/") || strings.HasPrefix(target, ".") || strings.HasPrefix(target, "~") || strings.ContainsAny(target, `\`) {
+		return false
+	}
+	_, namespace, appName, tag, err := DecomposeApplicationName(target)
+	if err != nil {
+		return false
+	}
+	for _, name := range []string{namespace, appName, tag} {
+		if name == "" || name == "." || name == ".." {
+			return false
+		}
+	}
+	lower := strings.ToLower(appName)
+	for _, suffix := range []string{".tgz", ".tar.gz", ".tar", ".zip"} {
+		if strings.HasSuffix(lower, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// remoteFiles returns the files of an application of the catalog, read from the local cache if possible.
+func (c *Catalog) remoteFiles(applicationID string) ([]*grpc_catalog_go.FileInfo, error) {
+	bundle, err := c.loadBundle(applicationID, "", false, &CacheOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return bundle.entries()
+}
+
+// localFiles returns the files of a local application directory or archive.
+func (c *Catalog) localFiles(appPath string, opts WalkOptions) ([]*grpc_catalog_go.FileInfo, error) {
+	path, cleanup, err := prepareApplicationPath(appPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return c.readLocalFiles(path, opts)
+}
+
+// readLocalFiles reads the files of an application directory that would be pushed.
+func (c *Catalog) readLocalFiles(path string, opts WalkOptions) ([]*grpc_catalog_go.FileInfo, error) {
+	names, err := c.readApp(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*grpc_catalog_go.FileInfo, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(fmt.Sprintf("%s/%s", path, name))
+		if err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to read file %s", name)
+		}
+		result = append(result, &grpc_catalog_go.FileInfo{Path: name, Data: data})
+	}
+	return result, nil
+}
+
+// compareFiles returns the differences between two sets of files, matching them by their normalized path.
+func compareFiles(from []*grpc_catalog_go.FileInfo, to []*grpc_catalog_go.FileInfo, opts *DiffOptions) *entities.DiffResult {
+	fromFiles := indexFiles(from)
+	toFiles := indexFiles(to)
+	paths := make([]string, 0, len(fromFiles)+len(toFiles))
+	for filePath := range fromFiles {
+		paths = append(paths, filePath)
+	}
+	for filePath := range toFiles {
+		if _, exists := fromFiles[filePath]; !exists {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+
+	result := &entities.DiffResult{Files: make([]*entities.FileDiff, 0)}
+	for _, filePath := range paths {
+		fromData, inFrom := fromFiles[filePath]
+		toData, inTo := toFiles[filePath]
+		fileDiff := &entities.FileDiff{Path: filePath, Status: entities.FileModified}
+		fromName, toName := "a/"+filePath, "b/"+filePath
+		switch {
+		case !inFrom:
+			fileDiff.Status = entities.FileAdded
+			fromName = "/dev/null"
+			result.NumAdded++
+		case !inTo:
+			fileDiff.Status = entities.FileRemoved
+			toName = "/dev/null"
+			result.NumRemoved++
+		case bytes.Equal(fromData, toData):
+			continue
+		default:
+			result.NumModified++
+		}
+		fileDiff.Binary = diff.IsBinary(fromData) || diff.IsBinary(toData)
+		if !fileDiff.Binary {
+			patch := diff.Unified(fromName, toName, fromData, toData, opts.Context)
+			fileDiff.Additions = patch.Additions
+			fileDiff.Deletions = patch.Deletions
+			if !opts.Stat {
+				fileDiff.Patch = patch.Text
+			}
+			result.Additions += patch.Additions
+			result.Deletions += patch.Deletions
+		}
+		result.Files = append(result.Files, fileDiff)
+	}
+	result.Metadata = compareMetadata(findMetadata(from), findMetadata(to))
+	return result
+}
+
+// indexFiles returns the content of the files indexed by their normalized path.
+func indexFiles(files []*grpc_catalog_go.FileInfo) map[string][]byte {
+	result := make(map[string][]byte, len(files))
+	for _, file := range files {
+		result[normalizeBundlePath(file.Path)] = file.Data
+	}
+	return result
+}
+
+// appMetadata with the fields of the application metadata compared by diff.
+type appMetadata struct {
+	// Name of the application.
+	Name string `yaml:"name"`
+	// Version of the application.
+	Version string `yaml:"version"`
+	// Requires with the entities needed to deploy the application.
+	Requires struct {
+		// Traits required by the application.
+		Traits []string `yaml:"traits"`
+		// Scopes required by the application.
+		Scopes []string `yaml:"scopes"`
+		// K8s with the Kubernetes entities required by the application.
+		K8s []k8sEntity `yaml:"k8s"`
+	} `yaml:"requires"`
+}
+
+// k8sEntity with a Kubernetes entity required by an application.
+type k8sEntity struct {
+	// APIVersion of the entity.
+	APIVersion string `yaml:"apiVersion"`
+	// Kind of the entity.
+	Kind string `yaml:"kind"`
+	// Name of the entity, if any.
+	Name string `yaml:"name"`
+}
+
+// String returns the textual representation of the entity.
+func (e k8sEntity) String() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s %s", e.APIVersion, e.Kind)
+	}
+	return fmt.Sprintf("%s %s/%s", e.APIVersion, e.Kind, e.Name)
+}
+
+// findMetadata returns the first application metadata document found in the YAML files, or nil if
+// the application does not contain metadata.
+func findMetadata(files []*grpc_catalog_go.FileInfo) *appMetadata {
+	for _, file := range files {
+		if !isYAMLFile(file.Path) {
+			continue
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(file.Data))
+		for {
+			var document yaml.Node
+			if err := decoder.Decode(&document); err != nil {
+				break
+			}
+			if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+				continue
+			}
+			kind := mappingValue(document.Content[0], "kind")
+			if kind == nil || kind.Value != MetadataKind {
+				continue
+			}
+			metadata := &appMetadata{}
+			if err := document.Decode(metadata); err != nil {
+				log.Debug().Err(err).Str("file", file.Path).Msg("invalid application metadata")
+				continue
+			}
+			return metadata
+		}
+	}
+	return nil
+}
+
+// compareMetadata returns the changes in the name, version and requirements of an application.
+func compareMetadata(from *appMetadata, to *appMetadata) []*entities.MetadataChange {
+	if from == nil {
+		from = &appMetadata{}
+	}
+	if to == nil {
+		to = &appMetadata{}
+	}
+	result := make([]*entities.MetadataChange, 0)
+	for _, field := range []struct {
+		name     string
+		from, to string
+	}{{"name", from.Name, to.Name}, {"version", from.Version, to.Version}} {
+		if field.from != field.to {
+			result = append(result, &entities.MetadataChange{Field: field.name, Status: entities.FileModified, From: field.from, To: field.to})
+		}
+	}
+	result = append(result, compareLists("requires.traits", from.Requires.Traits, to.Requires.Traits)...)
+	result = append(result, compareLists("requires.scopes", from.Requires.Scopes, to.Requires.Scopes)...)
+	result = append(result, compareLists("requires.k8s", k8sEntityNames(from.Requires.K8s), k8sEntityNames(to.Requires.K8s))...)
+	return result
+}
+
+// k8sEntityNames returns the textual representation of a list of entities.
+func k8sEntityNames(entitiesList []k8sEntity) []string {
+	result := make([]string, 0, len(entitiesList))
+	for _, entity := range entitiesList {
+		result = append(result, entity.String())
+	}
+	return result
+}
+
+// compareLists returns the values added to and removed from a list.
+func compareLists(field string, from []string, to []string) []*entities.MetadataChange {
+	result := make([]*entities.MetadataChange, 0)
+	for _, value := range from {
+		if !contains(to, value) {
+			result = append(result, &entities.MetadataChange{Field: field, Status: entities.FileRemoved, From: value})
+		}
+	}
+	for _, value := range to {
+		if !contains(from, value) {
+			result = append(result, &entities.MetadataChange{Field: field, Status: entities.FileAdded, To: value})
+		}
+	}
+	return result
+}
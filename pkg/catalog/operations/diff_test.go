@@ -0,0 +1,132 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Diff tests", func() {
+
+	ginkgo.It("Should classify the changed files", func() {
+		from := []*grpc_catalog_go.FileInfo{
+			{Path: "./metadata.yaml", Data: []byte("name: app\n")},
+			{Path: "./app.yaml", Data: []byte("kind: Application\n")},
+			{Path: "./removed.yaml", Data: []byte("kind: Removed\n")},
+			{Path: "./logo.png", Data: []byte{0x89, 'P', 'N', 'G', 0x00}},
+		}
+		to := []*grpc_catalog_go.FileInfo{
+			{Path: "/metadata.yaml", Data: []byte("name: renamed\n")},
+			{Path: "/app.yaml", Data: []byte("kind: Application\n")},
+			{Path: "/added.yaml", Data: []byte("kind: Added\n")},
+			{Path: "/logo.png", Data: []byte{0x89, 'P', 'N', 'G', 0x01}},
+		}
+		result := compareFiles(from, to, &DiffOptions{Context: 3})
+		gomega.Expect(result.HasChanges()).To(gomega.BeTrue())
+		gomega.Expect(result.NumAdded).To(gomega.Equal(1))
+		gomega.Expect(result.NumRemoved).To(gomega.Equal(1))
+		gomega.Expect(result.NumModified).To(gomega.Equal(2))
+		gomega.Expect(result.Files).To(gomega.HaveLen(4))
+
+		gomega.Expect(result.Files[0].Path).To(gomega.Equal("added.yaml"))
+		gomega.Expect(result.Files[0].Status).To(gomega.Equal(entities.FileAdded))
+		gomega.Expect(result.Files[0].Patch).To(gomega.ContainSubstring("--- /dev/null"))
+		gomega.Expect(result.Files[1].Path).To(gomega.Equal("logo.png"))
+		gomega.Expect(result.Files[1].Binary).To(gomega.BeTrue())
+		gomega.Expect(result.Files[1].Patch).To(gomega.BeEmpty())
+		gomega.Expect(result.Files[2].Path).To(gomega.Equal("metadata.yaml"))
+		gomega.Expect(result.Files[2].Patch).To(gomega.ContainSubstring("+name: renamed"))
+		gomega.Expect(result.Files[3].Status).To(gomega.Equal(entities.FileRemoved))
+
+		result = compareFiles(from, to, &DiffOptions{Stat: true})
+		gomega.Expect(result.Files[2].Additions).To(gomega.Equal(1))
+		gomega.Expect(result.Files[2].Patch).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("Should read the local files applying the ignore rules", func() {
+		appDir, err := os.MkdirTemp("", "catalog-diff")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(appDir)
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "metadata.yaml"), []byte("name: app\n"), 0644)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "notes.txt"), []byte("notes"), 0644)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, CatalogIgnoreFile), []byte("*.txt\n"), 0644)).To(gomega.Succeed())
+
+		files, err := (&Catalog{}).readLocalFiles(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		result := compareFiles([]*grpc_catalog_go.FileInfo{{Path: "./metadata.yaml", Data: []byte("name: app\n")}}, files, &DiffOptions{})
+		gomega.Expect(result.HasChanges()).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("Should report the changes of the application metadata", func() {
+		from := []*grpc_catalog_go.FileInfo{{Path: "./metadata.yaml", Data: []byte(`apiVersion: core.napptive.com/v1alpha1
+kind: ApplicationMetadata
+name: app
+version: 1.0
+requires:
+  traits: [ingress]
+  k8s:
+    - apiVersion: v1
+      kind: ConfigMap
+      name: settings
+`)}}
+		to := []*grpc_catalog_go.FileInfo{
+			{Path: "./app.yaml", Data: []byte("kind: Application\n")},
+			{Path: "./metadata.yaml", Data: []byte(`apiVersion: core.napptive.com/v1alpha1
+kind: ApplicationMetadata
+name: app
+version: 1.1
+requires:
+  traits: [ingress, autoscaler]
+  scopes: [network]
+`)}}
+		result := compareFiles(from, to, &DiffOptions{})
+		gomega.Expect(result.Metadata).To(gomega.ConsistOf(
+			&entities.MetadataChange{Field: "version", Status: entities.FileModified, From: "1.0", To: "1.1"},
+			&entities.MetadataChange{Field: "requires.traits", Status: entities.FileAdded, To: "autoscaler"},
+			&entities.MetadataChange{Field: "requires.scopes", Status: entities.FileAdded, To: "network"},
+			&entities.MetadataChange{Field: "requires.k8s", Status: entities.FileRemoved, From: "v1 ConfigMap/settings"},
+		))
+	})
+
+	ginkgo.It("Should distinguish local applications from catalog applications", func() {
+		for _, target := range []string{StdinPath, os.TempDir()} {
+			local, err := isLocalApplication(target)
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(local).To(gomega.BeTrue())
+		}
+		for _, target := range []string{"namespace/app:1.0", "namespace/app", "catalog.example.com/namespace/app"} {
+			local, err := isLocalApplication(target)
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(local).To(gomega.BeFalse())
+		}
+	})
+
+	ginkgo.It("Should report missing local applications", func() {
+		missing := filepath.Join(os.TempDir(), "catalog-missing", "app")
+		for _, target := range []string{"./app", "../namespace/app", "apps/app.tgz", "app", missing} {
+			_, err := isLocalApplication(target)
+			gomega.Expect(err).To(gomega.HaveOccurred(), target)
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring(target))
+		}
+	})
+})
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+)
+
+// DigestPrefix with the algorithm used to compute the application digests.
+const DigestPrefix = "sha256:"
+
+// normalizeBundlePath removes the leading ./ and / of a file path so that the paths produced
+// by loadApp and the ones returned by the catalog can be compared.
+func normalizeBundlePath(filePath string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(filePath, `\`, "/")), "/")
+}
+
+// writeDigestEntry adds a file to the digest. The path and the size are included so that
+// renaming or splitting files produces a different digest.
+func writeDigestEntry(h hash.Hash, filePath string, size int64, content io.Reader) error {
+	normalized := normalizeBundlePath(filePath)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint64(header, uint64(len(normalized)))
+	h.Write(header)
+	h.Write([]byte(normalized))
+	binary.BigEndian.PutUint64(header, uint64(size))
+	h.Write(header)
+	_, err := io.Copy(h, content)
+	return err
+}
+
+// formatDigest returns the textual representation of a digest.
+func formatDigest(h hash.Hash) string {
+	return DigestPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// localDigest computes the digest of the files of an application directory. The digest only depends
+// on the relative paths and the contents of the files.
+func localDigest(appPath string, files []appFile) (string, error) {
+	sorted := make([]appFile, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return normalizeBundlePath(sorted[i].name) < normalizeBundlePath(sorted[j].name)
+	})
+
+	h := sha256.New()
+	for _, file := range sorted {
+		if err := digestLocalFile(h, appPath, file); err != nil {
+			return "", err
+		}
+	}
+	return formatDigest(h), nil
+}
+
+// digestLocalFile adds the content of a file in disk to the digest.
+func digestLocalFile(h hash.Hash, appPath string, file appFile) error {
+	reader, err := os.Open(fmt.Sprintf("%s/%s", appPath, file.name))
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to read file %s", file.name)
+	}
+	defer reader.Close()
+	if err := writeDigestEntry(h, file.name, file.size, reader); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to read file %s", file.name)
+	}
+	return nil
+}
+
+// filesDigest computes the digest of a list of files received from the catalog.
+func filesDigest(files []*grpc_catalog_go.FileInfo) string {
+	sorted := make([]*grpc_catalog_go.FileInfo, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return normalizeBundlePath(sorted[i].Path) < normalizeBundlePath(sorted[j].Path)
+	})
+
+	h := sha256.New()
+	for _, file := range sorted {
+		// writing in a hash never fails
+		_ = writeDigestEntry(h, file.Path, int64(len(file.Data)), bytes.NewReader(file.Data))
+	}
+	return formatDigest(h)
+}
+
+// archiveDigest computes the digest of the files contained in a tar stream, compressed with gzip or not,
+// so that the digest of a pulled application matches the one of the pushed files.
+func archiveDigest(data []byte, source string) (string, error) {
+	files, err := archiveFiles(data, source)
+	if err != nil {
+		return "", err
+	}
+	return filesDigest(files), nil
+}
+
+// validateDigest checks the format of a digest provided by the user.
+func validateDigest(digest string) error {
+	value := strings.TrimPrefix(digest, DigestPrefix)
+	if _, err := hex.DecodeString(value); err != nil || value == digest || len(value) != 2*sha256.Size {
+		return nerrors.NewInvalidArgumentError("invalid digest %q, expecting %s followed by 64 hexadecimal characters", digest, DigestPrefix)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Digest tests", func() {
+
+	ginkgo.It("Should compute the same digest for local and remote files", func() {
+		appDir, err := os.MkdirTemp("", "catalog-digest")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(appDir)
+		gomega.Expect(os.MkdirAll(filepath.Join(appDir, "app"), 0755)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "metadata.yaml"), []byte("metadata"), 0644)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "app", "app.yaml"), []byte("application"), 0644)).To(gomega.Succeed())
+
+		names, err := (&Catalog{}).readApp(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		files, err := statAppFiles(appDir, names)
+		gomega.Expect(err).To(gomega.Succeed())
+		local, err := localDigest(appDir, files)
+		gomega.Expect(err).To(gomega.Succeed())
+
+		remote := filesDigest([]*grpc_catalog_go.FileInfo{
+			{Path: "app/app.yaml", Data: []byte("application")},
+			{Path: "./metadata.yaml", Data: []byte("metadata")},
+		})
+		gomega.Expect(remote).To(gomega.Equal(local))
+	})
+
+	ginkgo.It("Should change the digest when a file is renamed", func() {
+		original := filesDigest([]*grpc_catalog_go.FileInfo{{Path: "./a.yaml", Data: []byte("content")}})
+		renamed := filesDigest([]*grpc_catalog_go.FileInfo{{Path: "./b.yaml", Data: []byte("content")}})
+		gomega.Expect(renamed).NotTo(gomega.Equal(original))
+	})
+
+	ginkgo.It("Should compute the same digest for an archive and its files", func() {
+		archive := createTestArchive(map[string]string{"./metadata.yaml": "metadata", "./app/app.yaml": "application"})
+		digest, err := archiveDigest(archive, "test")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(digest).To(gomega.Equal(filesDigest([]*grpc_catalog_go.FileInfo{
+			{Path: "app/app.yaml", Data: []byte("application")},
+			{Path: "metadata.yaml", Data: []byte("metadata")},
+		})))
+		gomega.Expect(validateDigest(digest)).To(gomega.Succeed())
+	})
+
+	ginkgo.It("Should reject malformed digests", func() {
+		for _, digest := range []string{"", "sha256:", "sha256:abc", "md5:d41d8cd98f00b204e9800998ecf8427e", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"} {
+			gomega.Expect(validateDigest(digest)).NotTo(gomega.Succeed(), digest)
+		}
+	})
+
+	ginkgo.It("Should pin and verify digests with a lock file", func() {
+		dir, err := os.MkdirTemp("", "catalog-lock")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "catalog.lock")
+		digest := filesDigest([]*grpc_catalog_go.FileInfo{{Path: "metadata.yaml", Data: []byte("metadata")}})
+
+		lock, err := loadLockFile(path)
+		gomega.Expect(err).To(gomega.Succeed())
+		key, err := lockKey("namespace/app")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(key).To(gomega.Equal("namespace/app:latest"))
+		lock.Applications[key] = digest
+		gomega.Expect(lock.save(path)).To(gomega.Succeed())
+
+		lock, err = loadLockFile(path)
+		gomega.Expect(err).To(gomega.Succeed())
+		expected, err := expectedDigest(key, &PullOptions{}, lock)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(expected).To(gomega.Equal(digest))
+		_, err = expectedDigest(key, &PullOptions{Digest: DigestPrefix + strings.Repeat("0", 64)}, lock)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
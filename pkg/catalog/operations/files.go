@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/printer"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMaxMessageSize with the default maximum size of the gRPC messages accepted by the catalog-manager.
+	DefaultMaxMessageSize = 4 * 1024 * 1024
+	// messageOverhead with the bytes reserved for the protobuf encoding of an AddApplicationRequest.
+	messageOverhead = 64
+)
+
+// appFile with the information of a file that is part of an application.
+type appFile struct {
+	// name with the path relative to the application directory as sent in FileInfo.Path.
+	name string
+	// size of the file in bytes.
+	size int64
+	// data with the content of a file taken from an archive, nil if it is read from the application directory.
+	data []byte
+}
+
+// statAppFiles obtains the size of the files returned by loadApp.
+func statAppFiles(path string, names []string) ([]appFile, error) {
+	result := make([]appFile, 0, len(names))
+	for _, name := range names {
+		info, err := os.Stat(fmt.Sprintf("%s/%s", path, name))
+		if err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to read file %s", name)
+		}
+		result = append(result, appFile{name: name, size: info.Size()})
+	}
+	return result, nil
+}
+
+// checkFileSizes verifies that every file fits in a single message before starting the upload, so
+// that the catalog does not receive an incomplete application.
+func checkFileSizes(applicationID string, files []appFile, maxMessageSize int) error {
+	if maxMessageSize <= 0 {
+		maxMessageSize = DefaultMaxMessageSize
+	}
+	for _, file := range files {
+		limit := int64(maxMessageSize - messageOverhead - len(applicationID) - len(file.name))
+		if file.size > limit {
+			return nerrors.NewFailedPreconditionError(
+				"file %s (%s) exceeds the maximum size of %s that can be sent to the catalog. Exclude it using a %s file or increase --maxMessageSize if the catalog accepts bigger messages",
+				file.name, printer.HumanSize(file.size), printer.HumanSize(limit), CatalogIgnoreFile)
+		}
+	}
+	return nil
+}
+
+// readAppFile reads the content of a file checking that it has not grown since it was inspected.
+func readAppFile(path string, file appFile) ([]byte, error) {
+	if file.data != nil {
+		return file.data, nil
+	}
+	reader, err := os.Open(fmt.Sprintf("%s/%s", path, file.name))
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read file %s", file.name)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, file.size+1))
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read file %s", file.name)
+	}
+	if int64(len(data)) != file.size {
+		return nil, nerrors.NewFailedPreconditionError("file %s has been modified during the push operation", file.name)
+	}
+	return data, nil
+}
+
+// sendError returns a descriptive error if the catalog rejects a file because of its size.
+func sendError(file appFile, err error) error {
+	if status.Code(err) == codes.ResourceExhausted {
+		return nerrors.NewFailedPreconditionError("the catalog rejected file %s (%s) because it exceeds its maximum message size: %s",
+			file.name, printer.HumanSize(file.size), status.Convert(err).Message())
+	}
+	return err
+}
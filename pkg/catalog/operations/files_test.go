@@ -0,0 +1,73 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = ginkgo.Describe("File size tests", func() {
+
+	ginkgo.It("Should accept files that fit in a message", func() {
+		files := []appFile{{name: "./metadata.yaml", size: 100}, {name: "./app.yaml", size: 900}}
+		gomega.Expect(checkFileSizes("ns/app:1.0", files, 2048)).To(gomega.Succeed())
+		gomega.Expect(checkFileSizes("ns/app:1.0", files, 0)).To(gomega.Succeed())
+	})
+
+	ginkgo.It("Should reject a file over the maximum message size naming it", func() {
+		files := []appFile{{name: "./metadata.yaml", size: 100}, {name: "./big.bin", size: 4096}}
+		err := checkFileSizes("ns/app:1.0", files, 2048)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(nerrors.FromError(err).Code).To(gomega.Equal(nerrors.FailedPrecondition))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("file ./big.bin (4.0 KiB) exceeds the maximum size"))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("--maxMessageSize"))
+		gomega.Expect(err.Error()).NotTo(gomega.ContainSubstring("metadata.yaml"))
+
+		// The default limit applies when no size is given
+		files = []appFile{{name: "./huge.bin", size: DefaultMaxMessageSize}}
+		gomega.Expect(checkFileSizes("ns/app:1.0", files, 0)).NotTo(gomega.Succeed())
+	})
+
+	ginkgo.It("Should detect a file that changes after being inspected", func() {
+		dir, err := os.MkdirTemp("", "catalog-files")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(dir)
+		gomega.Expect(os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("kind: Application"), 0644)).To(gomega.Succeed())
+
+		data, err := readAppFile(dir, appFile{name: "app.yaml", size: 17})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(data)).To(gomega.Equal("kind: Application"))
+
+		_, err = readAppFile(dir, appFile{name: "app.yaml", size: 10})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("file app.yaml has been modified"))
+	})
+
+	ginkgo.It("Should name the file rejected by the catalog", func() {
+		err := sendError(appFile{name: "./big.bin", size: 4096}, status.Error(codes.ResourceExhausted, "message larger than max"))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("the catalog rejected file ./big.bin (4.0 KiB)"))
+		other := status.Error(codes.Unavailable, "down")
+		gomega.Expect(sendError(appFile{name: "./app.yaml"}, other)).To(gomega.Equal(other))
+	})
+})
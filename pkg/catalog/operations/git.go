@@ -0,0 +1,130 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// gitCommand with the name of the git binary.
+const gitCommand = "git"
+
+// runGit executes a git command in the given directory returning its trimmed output.
+func runGit(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gitCommand, append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", nerrors.NewFailedPreconditionError("git %s failed: %s", strings.Join(args, " "), gitErrorMessage(err, &stderr))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitErrorMessage returns the message written by git on stderr, or the error if it is empty.
+func gitErrorMessage(err error, stderr *bytes.Buffer) string {
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return message
+	}
+	return err.Error()
+}
+
+// prepareGitApplication extracts the files of the application directory as they are in the given git
+// reference, ignoring any uncommitted change. Symbolic links are extracted following the given policy.
+// The files are written in a temporary directory that is removed by the returned cleanup function.
+func prepareGitApplication(appPath string, gitRef string, symlinks string) (string, func(), error) {
+	noCleanup := func() {}
+	// git would parse a reference starting with a dash as an option
+	if gitRef == "" || strings.HasPrefix(gitRef, "-") {
+		return "", noCleanup, nerrors.NewInvalidArgumentError("%q is not a valid git reference", gitRef)
+	}
+	if info, err := os.Stat(appPath); err != nil || !info.IsDir() {
+		return "", noCleanup, nerrors.NewInvalidArgumentError("%s must be a directory inside a git repository when a git reference is used", appPath)
+	}
+	if _, err := runGit(appPath, "rev-parse", "--verify", "--quiet", fmt.Sprintf("%s^{commit}", gitRef)); err != nil {
+		return "", noCleanup, nerrors.NewInvalidArgumentError("%s is not a valid git reference in %s", gitRef, appPath)
+	}
+	topLevel, err := runGit(appPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", noCleanup, err
+	}
+	prefix, err := runGit(appPath, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", noCleanup, err
+	}
+	treeish := gitRef
+	if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+		treeish = fmt.Sprintf("%s:%s", gitRef, prefix)
+	}
+	log.Debug().Str("path", appPath).Str("treeish", treeish).Msg("reading application from git")
+
+	tempDir, err := os.MkdirTemp("", "catalog-push-")
+	if err != nil {
+		return "", noCleanup, nerrors.NewInternalErrorFrom(err, "unable to create temporary directory")
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warn().Err(err).Str("path", tempDir).Msg("unable to remove temporary directory")
+		}
+	}
+
+	var stderr bytes.Buffer
+	// git archive must be executed in the top level directory as the tree is already restricted to the prefix
+	cmd := exec.Command(gitCommand, "-C", topLevel, "archive", "--format=tar", treeish)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cleanup()
+		return "", noCleanup, nerrors.NewInternalErrorFrom(err, "unable to read git archive")
+	}
+	if err := cmd.Start(); err != nil {
+		cleanup()
+		return "", noCleanup, nerrors.NewFailedPreconditionError("unable to execute git: %s", err.Error())
+	}
+	if _, err := extractTar(stdout, treeish, tempDir, symlinks); err != nil {
+		// stop git as nobody is reading its output
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		cleanup()
+		return "", noCleanup, err
+	}
+	if err := cmd.Wait(); err != nil {
+		cleanup()
+		return "", noCleanup, nerrors.NewFailedPreconditionError("git archive %s failed: %s", treeish, gitErrorMessage(err, &stderr))
+	}
+	return tempDir, cleanup, nil
+}
+
+// defaultTagFromGit returns the application identifier with the git reference as tag when the
+// identifier has no tag and the reference is a git tag.
+func defaultTagFromGit(applicationID string, appPath string, gitRef string) string {
+	if hasTag(applicationID) || strings.ContainsAny(gitRef, "/:") || strings.HasPrefix(gitRef, "-") {
+		return applicationID
+	}
+	if _, err := runGit(appPath, "rev-parse", "--verify", "--quiet", fmt.Sprintf("refs/tags/%s", gitRef)); err != nil {
+		return applicationID
+	}
+	log.Debug().Str("tag", gitRef).Msg("using git tag as application tag")
+	return fmt.Sprintf("%s:%s", applicationID, gitRef)
+}
@@ -0,0 +1,161 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Git tests", func() {
+
+	var repoDir string
+	var appDir string
+
+	// git runs a git command in the test repository with a fixed identity.
+	git := func(args ...string) {
+		cmd := exec.Command(gitCommand, append([]string{"-C", repoDir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_NOSYSTEM=1", "HOME="+repoDir)
+		output, err := cmd.CombinedOutput()
+		gomega.Expect(err).To(gomega.Succeed(), string(output))
+	}
+
+	writeFile := func(name string, content string) {
+		path := filepath.Join(repoDir, name)
+		gomega.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(path, []byte(content), 0644)).To(gomega.Succeed())
+	}
+
+	readGitApp := func(ref string, opts WalkOptions) ([]string, error) {
+		path, cleanup, err := prepareGitApplication(appDir, ref, opts.Symlinks)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		names, err := (&Catalog{}).readApp(path, opts)
+		sort.Strings(names)
+		return names, err
+	}
+
+	ginkgo.BeforeEach(func() {
+		if _, err := exec.LookPath(gitCommand); err != nil {
+			ginkgo.Skip("git is not available")
+		}
+		dir, err := os.MkdirTemp("", "catalog-git")
+		gomega.Expect(err).To(gomega.Succeed())
+		repoDir = dir
+		appDir = filepath.Join(repoDir, "app")
+
+		git("init", "--quiet")
+		writeFile("README.md", "outside the application")
+		writeFile("app/metadata.yaml", "kind: ApplicationMetadata")
+		writeFile("app/conf/app.yaml", "version: 1")
+		git("add", ".")
+		git("commit", "--quiet", "-m", "first version")
+		git("tag", "v1.0")
+		git("branch", "release")
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(repoDir)
+	})
+
+	ginkgo.It("Should read the committed files of the application directory", func() {
+		writeFile("app/conf/app.yaml", "version: 2")
+		writeFile("app/untracked.yaml", "not committed")
+
+		path, cleanup, err := prepareGitApplication(appDir, "v1.0", SymlinksFollow)
+		gomega.Expect(err).To(gomega.Succeed())
+		content, err := os.ReadFile(filepath.Join(path, "conf", "app.yaml"))
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("version: 1"))
+		names, err := (&Catalog{}).readApp(path, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		sort.Strings(names)
+		gomega.Expect(names).To(gomega.Equal([]string{"./conf/app.yaml", "./metadata.yaml"}))
+
+		cleanup()
+		_, err = os.Stat(path)
+		gomega.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("Should read the files of a later commit", func() {
+		writeFile("app/conf/app.yaml", "version: 2")
+		git("commit", "--quiet", "-am", "second version")
+
+		path, cleanup, err := prepareGitApplication(appDir, "HEAD", SymlinksFollow)
+		gomega.Expect(err).To(gomega.Succeed())
+		defer cleanup()
+		content, err := os.ReadFile(filepath.Join(path, "conf", "app.yaml"))
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("version: 2"))
+	})
+
+	ginkgo.It("Should reject invalid references and paths", func() {
+		for _, ref := range []string{"", "missing", "--output=/tmp/evil", "-h"} {
+			_, _, err := prepareGitApplication(appDir, ref, SymlinksFollow)
+			gomega.Expect(err).To(gomega.HaveOccurred(), ref)
+		}
+		_, _, err := prepareGitApplication(filepath.Join(appDir, "metadata.yaml"), "v1.0", SymlinksFollow)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("Should apply the symbolic link policy to the committed links", func() {
+		gomega.Expect(os.Symlink("conf/app.yaml", filepath.Join(appDir, "link.yaml"))).To(gomega.Succeed())
+		git("add", ".")
+		git("commit", "--quiet", "-m", "link")
+
+		names, err := readGitApp("HEAD", WalkOptions{Symlinks: SymlinksFollow})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(names).To(gomega.Equal([]string{"./conf/app.yaml", "./link.yaml", "./metadata.yaml"}))
+
+		names, err = readGitApp("HEAD", WalkOptions{Symlinks: SymlinksSkip})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(names).To(gomega.Equal([]string{"./conf/app.yaml", "./metadata.yaml"}))
+
+		_, err = readGitApp("HEAD", WalkOptions{Symlinks: SymlinksError})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("Should reject committed links pointing outside the application", func() {
+		gomega.Expect(os.Symlink("../README.md", filepath.Join(appDir, "readme.md"))).To(gomega.Succeed())
+		git("add", ".")
+		git("commit", "--quiet", "-m", "external link")
+
+		_, err := readGitApp("HEAD", WalkOptions{Symlinks: SymlinksFollow, AllowExternalSymlinks: true})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		names, err := readGitApp("HEAD", WalkOptions{Symlinks: SymlinksSkip})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(names).To(gomega.Equal([]string{"./conf/app.yaml", "./metadata.yaml"}))
+	})
+
+	ginkgo.It("Should use a git tag as the default application tag", func() {
+		gomega.Expect(defaultTagFromGit("ns/app", appDir, "v1.0")).To(gomega.Equal("ns/app:v1.0"))
+		gomega.Expect(defaultTagFromGit("ns/app:1.0", appDir, "v1.0")).To(gomega.Equal("ns/app:1.0"))
+		gomega.Expect(defaultTagFromGit("ns/app", appDir, "release")).To(gomega.Equal("ns/app"))
+		gomega.Expect(defaultTagFromGit("ns/app", appDir, "HEAD")).To(gomega.Equal("ns/app"))
+		gomega.Expect(defaultTagFromGit("ns/app", appDir, "-v1.0")).To(gomega.Equal("ns/app"))
+	})
+})
@@ -49,9 +49,10 @@ func PrintResultOrError(printer printer.ResultPrinter, result interface{}, err e
 */
 
 
-func SaveFile (resultFile string, file *grpc_catalog_go.FileInfo) error {
+// SaveFile writes the content of a file received from the catalog in the resultFile path
+func SaveFile(resultFile string, file *grpc_catalog_go.FileInfo) error {
 	// Create output file
-	out, err := os.Create(fmt.Sprintf(file.Path))
+	out, err := os.Create(resultFile)
 	if err != nil {
 		return nerrors.NewInternalErrorFrom(err, "Error creating file")
 	}
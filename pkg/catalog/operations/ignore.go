@@ -0,0 +1,205 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/napptive/nerrors/pkg/nerrors"
+)
+
+// CatalogIgnoreFile with the name of the file containing the patterns of the files that must not be pushed.
+const CatalogIgnoreFile = ".catalogignore"
+
+// DefaultIgnorePatterns with the patterns that are always excluded from an application, unless
+// they are negated in the .catalogignore file.
+var DefaultIgnorePatterns = []string{
+	".git/",
+	".svn/",
+	".hg/",
+	".DS_Store",
+	"*.swp",
+	"*.swo",
+	"*~",
+	CatalogIgnoreFile,
+}
+
+// ignoreRule with a compiled gitignore-style pattern.
+type ignoreRule struct {
+	// pattern as written by the user.
+	pattern string
+	// source with the origin of the rule (file and line) for debugging purposes.
+	source string
+	// negate indicates that the matching paths must be included again.
+	negate bool
+	// dirOnly indicates that the rule only applies to directories.
+	dirOnly bool
+	// regex with the compiled pattern.
+	regex *regexp.Regexp
+}
+
+// ignoreRules with the ordered list of rules used to exclude files from an application.
+type ignoreRules struct {
+	rules []ignoreRule
+}
+
+// newIgnoreRules creates a set of rules containing the default patterns.
+func newIgnoreRules() *ignoreRules {
+	result := &ignoreRules{}
+	for _, pattern := range DefaultIgnorePatterns {
+		// default patterns are known to be valid
+		_ = result.add(pattern, "default")
+	}
+	return result
+}
+
+// loadIgnoreRules returns the default rules extended with the ones found in the .catalogignore file
+// of the application directory, if any.
+func loadIgnoreRules(appPath string) (*ignoreRules, error) {
+	result := newIgnoreRules()
+	ignorePath := filepath.Join(appPath, CatalogIgnoreFile)
+	file, err := os.Open(ignorePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read %s", ignorePath)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if err := result.add(scanner.Text(), fmt.Sprintf("%s:%d", CatalogIgnoreFile, lineNumber)); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read %s", ignorePath)
+	}
+	return result, nil
+}
+
+// add parses a gitignore-style line and appends it to the list of rules. Empty lines and
+// comments are discarded.
+func (ir *ignoreRules) add(line string, source string) error {
+	pattern := strings.TrimRight(line, " \t\r")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return nil
+	}
+	rule := ignoreRule{pattern: pattern, source: source}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return nil
+	}
+	// A pattern with a separator is relative to the application root, otherwise
+	// it matches at any level.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	expr := globToRegex(pattern)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	regex, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return nerrors.NewInvalidArgumentError("invalid ignore pattern %q in %s", line, source)
+	}
+	rule.regex = regex
+	ir.rules = append(ir.rules, rule)
+	return nil
+}
+
+// match checks if a path relative to the application root must be ignored. It returns
+// the rule that decided the result so that the decision can be logged.
+func (ir *ignoreRules) match(relativePath string, isDir bool) (bool, *ignoreRule) {
+	relativePath = strings.TrimPrefix(filepath.ToSlash(relativePath), "./")
+	var matched *ignoreRule
+	for index := range ir.rules {
+		rule := &ir.rules[index]
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.regex.MatchString(relativePath) {
+			matched = rule
+		}
+	}
+	if matched == nil {
+		return false, nil
+	}
+	return !matched.negate, matched
+}
+
+// globToRegex translates a gitignore glob into a regular expression.
+func globToRegex(pattern string) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				// "**/" matches zero or more directories, a trailing "**" matches everything inside.
+				if i+2 < len(pattern) && pattern[i+2] == '/' {
+					sb.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					sb.WriteString(".*")
+					i++
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == -1 {
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Ignore rules tests", func() {
+
+	ginkgo.It("Should ignore the default patterns", func() {
+		rules := newIgnoreRules()
+		ignored, _ := rules.match("./.git", true)
+		gomega.Expect(ignored).To(gomega.BeTrue())
+		ignored, _ = rules.match("./sub/.DS_Store", false)
+		gomega.Expect(ignored).To(gomega.BeTrue())
+		ignored, _ = rules.match("./app.yaml", false)
+		gomega.Expect(ignored).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("Should apply gitignore semantics", func() {
+		rules := newIgnoreRules()
+		gomega.Expect(rules.add("/build/", "test")).To(gomega.Succeed())
+		gomega.Expect(rules.add("*.log", "test")).To(gomega.Succeed())
+		gomega.Expect(rules.add("!keep.log", "test")).To(gomega.Succeed())
+		gomega.Expect(rules.add("docs/**/*.tmp", "test")).To(gomega.Succeed())
+
+		testCases := map[string]bool{
+			"./build":             true,
+			"./sub/build":         false,
+			"./a/b/output.log":    true,
+			"./a/keep.log":        false,
+			"./docs/x/y/file.tmp": true,
+			"./docs/file.tmp":     true,
+			"./other/file.tmp":    false,
+		}
+		for path, expected := range testCases {
+			ignored, _ := rules.match(path, path == "./build" || path == "./sub/build")
+			gomega.Expect(ignored).To(gomega.Equal(expected), path)
+		}
+	})
+
+	ginkgo.It("Should skip the ignored files when loading an application", func() {
+		appDir, err := os.MkdirTemp("", "catalog-ignore")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(appDir)
+
+		for _, name := range []string{"app.yaml", "secret.env", ".git/config", "nested/README.md"} {
+			target := filepath.Join(appDir, name)
+			gomega.Expect(os.MkdirAll(filepath.Dir(target), 0755)).To(gomega.Succeed())
+			gomega.Expect(os.WriteFile(target, []byte("content"), 0644)).To(gomega.Succeed())
+		}
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, CatalogIgnoreFile), []byte("# secrets\n*.env\n"), 0644)).To(gomega.Succeed())
+
+		names, err := (&Catalog{}).readApp(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		sort.Strings(names)
+		gomega.Expect(names).To(gomega.Equal([]string{"./app.yaml", "./nested/README.md"}))
+	})
+})
@@ -0,0 +1,146 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"sort"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/cache"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// InfoOptions with the options of the info operation.
+type InfoOptions struct {
+	CacheOptions
+	// Files lists the files of the application with their sizes.
+	Files bool
+	// File with the path of a file of the application whose content is printed.
+	File string
+	// NoReadme omits the README of the application.
+	NoReadme bool
+}
+
+// Info gets application information. The information is stored in the local cache so that it can be
+// read in offline mode. The catalog does not return the files of the application so the application
+// is downloaded, or read from the local cache, to list them or to print one of them.
+func (c *Catalog) Info(application string, opts *InfoOptions) error {
+	log.Debug().Str("application", application).Bool("files", opts.Files).Str("file", opts.File).Msg("Info received!")
+	if opts.File != "" {
+		return c.printApplicationFile(application, opts)
+	}
+
+	ref, err := c.cacheRef(application)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	response, err := c.applicationInfo(ref, application, opts)
+	if err == nil && opts.NoReadme {
+		response.ReadmeFile = nil
+	}
+	if err != nil || !opts.Files {
+		return c.ResultPrinter.PrintResultOrError(response, err)
+	}
+
+	bundle, err := c.loadBundle(application, "", false, &opts.CacheOptions)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	files, err := bundle.entries()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	result := &entities.ApplicationInfo{InfoApplicationResponse: response, Files: applicationFiles(files)}
+	for _, file := range result.Files {
+		result.TotalSize += file.Size
+	}
+	return c.ResultPrinter.PrintResultOrError(result, nil)
+}
+
+// applicationInfo returns the information of an application from the catalog, or from the local cache in offline mode.
+func (c *Catalog) applicationInfo(ref cache.Ref, application string, opts *InfoOptions) (*grpc_catalog_go.InfoApplicationResponse, error) {
+	if opts.Offline {
+		return c.cachedInfo(ref)
+	}
+
+	// Connection
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, application)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Client
+	client := grpc_catalog_go.NewCatalogClient(conn)
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+
+	response, err := client.Info(ctx, &grpc_catalog_go.InfoApplicationRequest{ApplicationId: application})
+	if err != nil {
+		return nil, err
+	}
+	c.storeInfo(ref, response)
+	return response, nil
+}
+
+// printApplicationFile writes the content of a file of the application to the standard output.
+func (c *Catalog) printApplicationFile(application string, opts *InfoOptions) error {
+	bundle, err := c.loadBundle(application, "", false, &opts.CacheOptions)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	files, err := bundle.entries()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	file := findApplicationFile(files, opts.File)
+	if file == nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewNotFoundError("file %s not found in %s", normalizeBundlePath(opts.File), application))
+	}
+	if _, err := os.Stdout.Write(file.Data); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write %s", opts.File)
+	}
+	return nil
+}
+
+// applicationFiles returns the paths and sizes of the files of an application sorted by path.
+func applicationFiles(files []*grpc_catalog_go.FileInfo) []*entities.ApplicationFile {
+	result := make([]*entities.ApplicationFile, 0, len(files))
+	for _, file := range files {
+		result = append(result, &entities.ApplicationFile{Path: normalizeBundlePath(file.Path), Size: int64(len(file.Data))})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+// findApplicationFile returns the file of an application with the given path, ignoring the leading ./ or /,
+// or nil if the application does not contain it.
+func findApplicationFile(files []*grpc_catalog_go.FileInfo, filePath string) *grpc_catalog_go.FileInfo {
+	target := normalizeBundlePath(filePath)
+	for _, file := range files {
+		if normalizeBundlePath(file.Path) == target {
+			return file
+		}
+	}
+	return nil
+}
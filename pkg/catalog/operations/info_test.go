@@ -0,0 +1,48 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Info tests", func() {
+
+	files := []*grpc_catalog_go.FileInfo{
+		{Path: "./metadata.yaml", Data: []byte("kind: ApplicationMetadata")},
+		{Path: "./app/app.yaml", Data: []byte("kind: Application")},
+		{Path: "/README.md", Data: []byte("# App")},
+	}
+
+	ginkgo.It("Should list the files of an application sorted by path", func() {
+		result := applicationFiles(files)
+		gomega.Expect(result).To(gomega.HaveLen(3))
+		gomega.Expect(result[0].Path).To(gomega.Equal("README.md"))
+		gomega.Expect(result[0].Size).To(gomega.Equal(int64(5)))
+		gomega.Expect(result[1].Path).To(gomega.Equal("app/app.yaml"))
+		gomega.Expect(result[2].Path).To(gomega.Equal("metadata.yaml"))
+	})
+
+	ginkgo.It("Should find a file with or without the leading ./", func() {
+		gomega.Expect(findApplicationFile(files, "app/app.yaml")).To(gomega.Equal(files[1]))
+		gomega.Expect(findApplicationFile(files, "./app/app.yaml")).To(gomega.Equal(files[1]))
+		gomega.Expect(findApplicationFile(files, "README.md")).To(gomega.Equal(files[2]))
+		gomega.Expect(findApplicationFile(files, "app")).To(gomega.BeNil())
+	})
+})
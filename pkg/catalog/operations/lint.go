@@ -0,0 +1,242 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MetadataKind with the kind of the document describing a catalog application.
+	MetadataKind = "ApplicationMetadata"
+	// MetadataAPIVersion with the expected apiVersion of the application metadata.
+	MetadataAPIVersion = "core.napptive.com/v1alpha1"
+	// ReadmeFile with the name of the file shown as the application documentation.
+	ReadmeFile = "README.md"
+)
+
+// yamlErrorLine extracts the line number from the errors returned by the YAML parser.
+var yamlErrorLine = regexp.MustCompile(`line (\d+): `)
+
+// Lint validates an application directory without contacting the catalog. It reports all
+// the problems found and returns an error if any of them prevents the application from being pushed.
+func (c *Catalog) Lint(path string) error {
+	result, err := c.lintApp(path)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if err := c.ResultPrinter.PrintResultOrError(result, nil); err != nil {
+		return err
+	}
+	if result.NumErrors > 0 {
+		return nerrors.NewFailedPreconditionError("application %s is not valid: %d errors found", path, result.NumErrors)
+	}
+	return nil
+}
+
+// lintApp walks the application directory as loadApp does and validates every YAML document.
+func (c *Catalog) lintApp(path string) (*entities.LintResult, error) {
+	names, err := c.readApp(path, WalkOptions{})
+	if err != nil {
+		return nil, err
+	}
+	log.Debug().Interface("names", names).Msg("Files found")
+
+	result := &entities.LintResult{Path: path, NumFiles: len(names)}
+	if len(names) == 0 {
+		result.AddProblem(entities.LintError, ".", 0, "the application directory does not contain any file")
+		return result, nil
+	}
+
+	numMetadata := 0
+	hasReadme := false
+	for _, name := range names {
+		if strings.EqualFold(name, fmt.Sprintf("./%s", ReadmeFile)) {
+			hasReadme = true
+		}
+		if !isYAMLFile(name) {
+			continue
+		}
+		data, err := os.ReadFile(fmt.Sprintf("%s/%s", path, name))
+		if err != nil {
+			return nil, nerrors.NewInternalErrorFrom(err, "unable to read %s", name)
+		}
+		numMetadata += lintYAMLFile(name, data, result)
+	}
+
+	switch {
+	case numMetadata == 0:
+		result.AddProblem(entities.LintError, ".", 0, "required metadata file not found: no YAML document with kind %s", MetadataKind)
+	case numMetadata > 1:
+		result.AddProblem(entities.LintError, ".", 0, "%d documents with kind %s found, only one is allowed", numMetadata, MetadataKind)
+	}
+	if !hasReadme {
+		result.AddProblem(entities.LintWarning, ".", 0, "%s not found, the application will not have documentation", ReadmeFile)
+	}
+	return result, nil
+}
+
+// isYAMLFile checks the extension of a file.
+func isYAMLFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml")
+}
+
+// lintYAMLFile parses all the documents of a file adding the problems to the result. It returns the
+// number of application metadata documents found.
+func lintYAMLFile(name string, data []byte, result *entities.LintResult) int {
+	numMetadata := 0
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return numMetadata
+		}
+		if err != nil {
+			result.AddProblem(entities.LintError, name, yamlLine(err), "invalid YAML: %s", yamlMessage(err))
+			return numMetadata
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		root := document.Content[0]
+		if root.Kind != yaml.MappingNode {
+			result.AddProblem(entities.LintWarning, name, root.Line, "document is not a YAML mapping")
+			continue
+		}
+		kind := mappingValue(root, "kind")
+		if kind == nil {
+			result.AddProblem(entities.LintWarning, name, root.Line, "document without kind")
+			continue
+		}
+		if kind.Value == MetadataKind {
+			numMetadata++
+			lintMetadata(name, root, result)
+		}
+	}
+}
+
+// lintMetadata validates the fields of the application metadata shown by the info command.
+func lintMetadata(name string, root *yaml.Node, result *entities.LintResult) {
+	apiVersion := mappingValue(root, "apiVersion")
+	if apiVersion == nil || apiVersion.Value == "" {
+		result.AddProblem(entities.LintError, name, root.Line, "metadata apiVersion is required")
+	} else if apiVersion.Value != MetadataAPIVersion {
+		result.AddProblem(entities.LintWarning, name, apiVersion.Line, "unexpected metadata apiVersion %s, expecting %s", apiVersion.Value, MetadataAPIVersion)
+	}
+
+	lintRequiredString(name, root, "name", entities.LintError, result)
+	lintRequiredString(name, root, "description", entities.LintWarning, result)
+
+	requires := mappingValue(root, "requires")
+	if requires == nil {
+		return
+	}
+	if requires.Kind != yaml.MappingNode {
+		result.AddProblem(entities.LintError, name, requires.Line, "requires must be a mapping with traits, scopes and k8s")
+		return
+	}
+	for _, key := range []string{"traits", "scopes"} {
+		lintStringList(name, requires, key, result)
+	}
+	k8s := mappingValue(requires, "k8s")
+	if k8s == nil {
+		return
+	}
+	if k8s.Kind != yaml.SequenceNode {
+		result.AddProblem(entities.LintError, name, k8s.Line, "requires.k8s must be a list of entities")
+		return
+	}
+	for _, entity := range k8s.Content {
+		if entity.Kind != yaml.MappingNode {
+			result.AddProblem(entities.LintError, name, entity.Line, "requires.k8s entries must contain apiVersion and kind")
+			continue
+		}
+		for _, key := range []string{"apiVersion", "kind"} {
+			if value := mappingValue(entity, key); value == nil || value.Kind != yaml.ScalarNode || value.Value == "" {
+				result.AddProblem(entities.LintError, name, entity.Line, "requires.k8s entry without %s", key)
+			}
+		}
+	}
+}
+
+// lintRequiredString checks that a field is a non-empty string.
+func lintRequiredString(name string, root *yaml.Node, key string, severity string, result *entities.LintResult) {
+	value := mappingValue(root, key)
+	if value == nil || value.Kind != yaml.ScalarNode || strings.TrimSpace(value.Value) == "" {
+		line := root.Line
+		if value != nil {
+			line = value.Line
+		}
+		result.AddProblem(severity, name, line, "metadata %s is required", key)
+	}
+}
+
+// lintStringList checks that an optional field is a list of non-empty strings.
+func lintStringList(name string, root *yaml.Node, key string, result *entities.LintResult) {
+	value := mappingValue(root, key)
+	if value == nil {
+		return
+	}
+	if value.Kind != yaml.SequenceNode {
+		result.AddProblem(entities.LintError, name, value.Line, "requires.%s must be a list", key)
+		return
+	}
+	for _, item := range value.Content {
+		if item.Kind != yaml.ScalarNode || item.Value == "" {
+			result.AddProblem(entities.LintError, name, item.Line, "requires.%s entries must be non-empty strings", key)
+		}
+	}
+}
+
+// mappingValue returns the value associated with a key in a mapping node, or nil if not found.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlLine extracts the line of a parsing error, 0 if not available.
+func yamlLine(err error) int {
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(match[1])
+	return line
+}
+
+// yamlMessage removes the prefix and the line information from a parsing error.
+func yamlMessage(err error) string {
+	return yamlErrorLine.ReplaceAllString(strings.TrimPrefix(err.Error(), "yaml: "), "")
+}
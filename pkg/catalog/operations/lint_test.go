@@ -0,0 +1,66 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const validMetadata = `apiVersion: core.napptive.com/v1alpha1
+kind: ApplicationMetadata
+name: "Test app"
+description: Application used in the tests
+requires:
+  traits:
+    - ingress
+  k8s:
+    - apiVersion: v1
+      kind: ConfigMap
+`
+
+var _ = ginkgo.Describe("Lint tests", func() {
+
+	ginkgo.It("Should accept valid metadata", func() {
+		result := &entities.LintResult{}
+		numMetadata := lintYAMLFile("./metadata.yaml", []byte(validMetadata), result)
+		gomega.Expect(numMetadata).To(gomega.Equal(1))
+		gomega.Expect(result.Problems).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("Should report invalid metadata fields with their line", func() {
+		metadata := `apiVersion: core.napptive.com/v1alpha1
+kind: ApplicationMetadata
+description: Application used in the tests
+requires:
+  scopes: ingress
+`
+		result := &entities.LintResult{}
+		numMetadata := lintYAMLFile("./metadata.yaml", []byte(metadata), result)
+		gomega.Expect(numMetadata).To(gomega.Equal(1))
+		gomega.Expect(result.NumErrors).To(gomega.Equal(2))
+		gomega.Expect(result.Problems[1].Line).To(gomega.Equal(5))
+	})
+
+	ginkgo.It("Should report syntax errors", func() {
+		result := &entities.LintResult{}
+		lintYAMLFile("./app.yaml", []byte("kind: Application\nspec: [\n"), result)
+		gomega.Expect(result.NumErrors).To(gomega.Equal(1))
+		gomega.Expect(result.Problems[0].Line).To(gomega.Equal(2))
+	})
+})
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// lockFile with the digests pinned for each application.
+type lockFile struct {
+	// Applications maps [catalog/]namespace/appName:tag to the expected digest.
+	Applications map[string]string `yaml:"applications"`
+}
+
+// lockKey returns the identifier of an application in the lock file, with the tag set to latest
+// if it is not provided so that both forms share the same entry.
+func lockKey(applicationID string) (string, error) {
+	catalogURL, namespace, appName, tag, err := DecomposeApplicationName(applicationID)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s/%s:%s", namespace, appName, tag)
+	if catalogURL != "" {
+		key = fmt.Sprintf("%s/%s", catalogURL, key)
+	}
+	return key, nil
+}
+
+// loadLockFile reads a lock file. A missing file is equivalent to an empty one.
+func loadLockFile(path string) (*lockFile, error) {
+	result := &lockFile{Applications: make(map[string]string)}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to read lock file %s", path)
+	}
+	if err := yaml.Unmarshal(content, result); err != nil {
+		return nil, nerrors.NewInvalidArgumentError("invalid lock file %s: %s", path, err.Error())
+	}
+	if result.Applications == nil {
+		result.Applications = make(map[string]string)
+	}
+	for key, digest := range result.Applications {
+		if err := validateDigest(digest); err != nil {
+			return nil, nerrors.NewInvalidArgumentError("invalid lock file %s, entry %s: %s", path, key, nerrors.FromError(err).Msg)
+		}
+	}
+	return result, nil
+}
+
+// save writes the lock file.
+func (lf *lockFile) save(path string) error {
+	content, err := yaml.Marshal(lf)
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to encode lock file")
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to write lock file %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/prompt"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultPruneKeep with the number of tags kept by default by the prune operation.
+const DefaultPruneKeep = 10
+
+// DefaultProtectedTags with the patterns of the tags that are never pruned.
+var DefaultProtectedTags = []string{"latest", "v*-stable"}
+
+// PruneOptions with the options of the prune operation.
+type PruneOptions struct {
+	BackupOptions
+	// Keep with the number of most recent tags that are kept.
+	Keep int
+	// KeepLatestMinor keeps the latest patch version of every minor version.
+	KeepLatestMinor bool
+	// Protect with additional patterns of tags that are never pruned.
+	Protect []string
+	// Yes removes the tags without asking for confirmation.
+	Yes bool
+	// DryRun prints the tags that would be removed without removing them.
+	DryRun bool
+}
+
+// protectedPatterns returns the default patterns and the ones provided by the user.
+func (o *PruneOptions) protectedPatterns() ([]string, error) {
+	result := append([]string{}, DefaultProtectedTags...)
+	for _, pattern := range o.Protect {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, nerrors.NewInvalidArgumentError("invalid protected tag pattern %q", pattern)
+		}
+		result = append(result, pattern)
+	}
+	return result, nil
+}
+
+// Prune removes the oldest tags of an application keeping the most recent ones. Tags are ordered
+// following semantic versioning, tags that are not versions are ordered lexically and considered
+// older than any version. Tags matching a protected pattern are never removed.
+func (c *Catalog) Prune(applicationID string, opts *PruneOptions) error {
+	log.Debug().Str("applicationID", applicationID).Int("keep", opts.Keep).Bool("dryRun", opts.DryRun).Msg("Prune received!")
+	if hasTag(applicationID) {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("the application to prune must not contain a tag"))
+	}
+	if opts.Keep < 0 {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("the number of tags to keep cannot be negative"))
+	}
+	protected, err := opts.protectedPatterns()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	catalogURL, err := connection.GetURL(&c.cfg.ConnectionConfig, applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	_, namespace, appName, _, err := DecomposeApplicationName(applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+
+	// Connection
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s:%d",
+			c.cfg.CatalogAddress, c.cfg.CatalogPort))
+	}
+	defer conn.Close()
+	client := grpc_catalog_go.NewCatalogClient(conn)
+
+	ctx, cancel := c.AuthToken.GetContext()
+	app, err := findApplication(ctx, client, namespace, appName)
+	cancel()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if app == nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewNotFoundError("application %s not found in %s", applicationID, catalogURL))
+	}
+	tags := make([]string, 0, len(app.TagMetadataName))
+	for tag := range app.TagMetadataName {
+		tags = append(tags, tag)
+	}
+
+	result := &entities.PruneResult{
+		ApplicationID: fmt.Sprintf("%s/%s", namespace, appName),
+		CatalogURL:    catalogURL,
+		Tags:          prunePlan(tags, opts.Keep, opts.KeepLatestMinor, protected),
+	}
+	result.Count()
+	if opts.DryRun || result.NumRemoved == 0 {
+		return c.ResultPrinter.PrintResultOrError(result, nil)
+	}
+	if !opts.Yes && prompt.IsInteractive() {
+		if err := c.ResultPrinter.PrintResultOrError(result, nil); err != nil {
+			return err
+		}
+		confirmed, err := prompt.Confirm(os.Stdin, os.Stderr, fmt.Sprintf("Remove %d tags of %s from %s?", result.NumRemoved, result.ApplicationID, catalogURL))
+		if err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+		if !confirmed {
+			return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewCanceledError("prune canceled, no tags have been removed"))
+		}
+	}
+
+	if opts.Backup {
+		targets := make([]*entities.RemoveTarget, 0, result.NumRemoved)
+		for _, tag := range result.Tags {
+			if tag.Action == entities.PruneRemove {
+				targets = append(targets, &entities.RemoveTarget{
+					ApplicationID: fmt.Sprintf("%s:%s", result.ApplicationID, tag.Tag),
+					Name:          app.TagMetadataName[tag.Tag],
+					Private:       app.Private,
+				})
+			}
+		}
+		if _, err := c.backupApplications(client, pruneOperation, catalogURL, targets); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+
+	for _, tag := range result.Tags {
+		if tag.Action != entities.PruneRemove {
+			continue
+		}
+		tagID := fmt.Sprintf("%s:%s", result.ApplicationID, tag.Tag)
+		ctx, cancel := c.AuthToken.GetContext()
+		_, err := client.Remove(ctx, &grpc_catalog_go.RemoveApplicationRequest{ApplicationId: tagID})
+		cancel()
+		if err != nil {
+			log.Debug().Err(err).Str("applicationID", tagID).Msg("remove failed")
+			tag.Action = entities.PruneFailed
+			tag.Info = nerrors.FromGRPC(err).Error()
+			continue
+		}
+		tag.Action = entities.PruneRemoved
+	}
+	result.Count()
+	if err := c.ResultPrinter.PrintResultOrError(result, nil); err != nil {
+		return err
+	}
+	if result.NumFailed > 0 {
+		return nerrors.NewInternalError("%d of %d tags could not be removed", result.NumFailed, result.NumFailed+result.NumRemoved)
+	}
+	return nil
+}
+
+// prunePlan decides which tags are kept, ordering them from the most recent one.
+func prunePlan(tags []string, keep int, keepLatestMinor bool, protected []string) []*entities.PruneTag {
+	sorted := append([]string{}, tags...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareTags(sorted[i], sorted[j]) > 0
+	})
+
+	result := make([]*entities.PruneTag, 0, len(sorted))
+	numRecent := 0
+	minorVersions := make(map[string]bool)
+	for _, tag := range sorted {
+		entry := &entities.PruneTag{Tag: tag, Action: entities.PruneKeep}
+		latestMinor := false
+		if version, isVersion := parseSemanticVersion(tag); isVersion && len(version.prerelease) == 0 {
+			minor := fmt.Sprintf("%d.%d", version.major, version.minor)
+			latestMinor = !minorVersions[minor]
+			minorVersions[minor] = true
+		}
+		if pattern := protectedBy(tag, protected); pattern != "" {
+			entry.Info = fmt.Sprintf("protected by %s", pattern)
+		} else if numRecent < keep {
+			numRecent++
+			entry.Info = fmt.Sprintf("one of the %d most recent tags", keep)
+		} else if keepLatestMinor && latestMinor {
+			entry.Info = "latest patch of its minor version"
+		} else {
+			entry.Action = entities.PruneRemove
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// protectedBy returns the first pattern matching the tag, or an empty string if the tag is not protected.
+func protectedBy(tag string, patterns []string) string {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, tag); matched {
+			return pattern
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"sort"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Prune tests", func() {
+
+	planActions := func(plan []*entities.PruneTag) map[string]string {
+		result := make(map[string]string)
+		for _, tag := range plan {
+			result[tag.Tag] = tag.Action
+		}
+		return result
+	}
+
+	ginkgo.It("Should order the tags following semantic versioning", func() {
+		tags := []string{"1.10.0", "ci-2", "1.2.0", "v1.9.3", "1.2.0-rc.10", "1.2.0-rc.2", "1.2.0-alpha", "2.0", "ci-10"}
+		sort.Slice(tags, func(i, j int) bool {
+			return compareTags(tags[i], tags[j]) < 0
+		})
+		gomega.Expect(tags).To(gomega.Equal([]string{"ci-10", "ci-2", "1.2.0-alpha", "1.2.0-rc.2", "1.2.0-rc.10", "1.2.0", "v1.9.3", "1.10.0", "2.0"}))
+
+		_, isVersion := parseSemanticVersion("1.02.0")
+		gomega.Expect(isVersion).To(gomega.BeFalse())
+		_, isVersion = parseSemanticVersion("1.2.3.4")
+		gomega.Expect(isVersion).To(gomega.BeFalse())
+		version, isVersion := parseSemanticVersion("v1.2.3+build.5")
+		gomega.Expect(isVersion).To(gomega.BeTrue())
+		gomega.Expect(version.patch).To(gomega.Equal(3))
+	})
+
+	ginkgo.It("Should keep the most recent and the protected tags", func() {
+		plan := prunePlan([]string{"1.0.0", "1.1.0", "1.2.0", "latest", "v1-stable", "ci-1"}, 2, false, DefaultProtectedTags)
+		gomega.Expect(plan[0].Tag).To(gomega.Equal("1.2.0"))
+		gomega.Expect(planActions(plan)).To(gomega.Equal(map[string]string{
+			"1.2.0":     entities.PruneKeep,
+			"1.1.0":     entities.PruneKeep,
+			"1.0.0":     entities.PruneRemove,
+			"latest":    entities.PruneKeep,
+			"v1-stable": entities.PruneKeep,
+			"ci-1":      entities.PruneRemove,
+		}))
+	})
+
+	ginkgo.It("Should keep the latest patch of every minor version", func() {
+		plan := prunePlan([]string{"1.0.0", "1.0.1", "1.1.0", "1.1.1", "1.2.0"}, 1, true, nil)
+		gomega.Expect(planActions(plan)).To(gomega.Equal(map[string]string{
+			"1.2.0": entities.PruneKeep,
+			"1.1.1": entities.PruneKeep,
+			"1.1.0": entities.PruneRemove,
+			"1.0.1": entities.PruneKeep,
+			"1.0.0": entities.PruneRemove,
+		}))
+	})
+
+	ginkgo.It("Should reject invalid protected patterns", func() {
+		patterns, err := (&PruneOptions{Protect: []string{"release-*"}}).protectedPatterns()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(patterns).To(gomega.ContainElements("latest", "v*-stable", "release-*"))
+		_, err = (&PruneOptions{Protect: []string{"release-["}}).protectedPatterns()
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
@@ -0,0 +1,418 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/printer"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/progress"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// PullFormatTGZ downloads the application as a tgz file.
+	PullFormatTGZ = "tgz"
+	// PullFormatTree downloads the application files uncompressed and writes them in a directory.
+	PullFormatTree = "tree"
+)
+
+// StdoutPath with the output used to write the application to the standard output.
+const StdoutPath = "-"
+
+// PullOptions with the options that modify the behavior of the pull operation.
+type PullOptions struct {
+	BulkPullOptions
+	CacheOptions
+	// Output with the file or directory where the application is written. If empty, the application
+	// is written in the current directory using the application name.
+	Output string
+	// Extract writes the application files in the output directory instead of the tgz file.
+	Extract bool
+	// Format in which the application is downloaded: tgz or tree.
+	Format string
+	// Force overwrites existing files.
+	Force bool
+	// Digest with the expected digest of the application.
+	Digest string
+	// LockFile with the path of a file with the expected digests of the applications. Applications
+	// without an entry are added to the file once pulled.
+	LockFile string
+}
+
+// toDirectory checks if the application files are written in a directory.
+func (o *PullOptions) toDirectory() bool {
+	return o.Extract || o.Format == PullFormatTree
+}
+
+// Pull downloads application files
+func (c *Catalog) Pull(applicationID string, opts *PullOptions) error {
+	log.Debug().Str("applicationID", applicationID).Str("output", opts.Output).Bool("extract", opts.Extract).Msg("Pull received!")
+
+	// Get the application name
+	_, _, appName, _, err := DecomposeApplicationName(applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if appName == "" || appName == "." || appName == ".." || strings.ContainsAny(appName, `\`) {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("invalid application name %q", appName))
+	}
+	if opts.Format != "" && opts.Format != PullFormatTGZ && opts.Format != PullFormatTree {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("invalid format %q, expecting %s or %s", opts.Format, PullFormatTGZ, PullFormatTree))
+	}
+	toStdout := opts.Output == StdoutPath
+	if toStdout {
+		// the standard output is reserved for the application
+		if c.ResultPrinter, err = printer.GetPrinterTo(c.cfg.PrinterType, os.Stderr); err != nil {
+			return err
+		}
+		if opts.toDirectory() {
+			return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInvalidArgumentError("only tgz applications can be written to the standard output"))
+		}
+		if isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+			return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewFailedPreconditionError("refusing to write the application to a terminal, redirect the standard output"))
+		}
+	}
+	target := pullTarget(appName, opts)
+	if opts.Digest != "" {
+		if err := validateDigest(opts.Digest); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+	var lock *lockFile
+	if opts.LockFile != "" {
+		if lock, err = loadLockFile(opts.LockFile); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+	key, err := lockKey(applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	expected, err := expectedDigest(key, opts, lock)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if !opts.toDirectory() && !toStdout {
+		// fail before downloading the application
+		if err := checkOverwrite(target, opts.Force); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+
+	tree := opts.Format == PullFormatTree
+	bundle, err := c.loadBundle(applicationID, expected, tree, &opts.CacheOptions)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+
+	// Verify the content before writing anything
+	if expected != "" && !strings.EqualFold(expected, bundle.digest) {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewFailedPreconditionError(
+			"digest mismatch for %s, expected %s but received %s. No file has been written", applicationID, expected, bundle.digest))
+	}
+
+	result := &entities.PullResult{ApplicationID: applicationID, Path: target, Digest: bundle.digest, Verified: expected != "", Cached: bundle.cached}
+	if tree {
+		var files []*grpc_catalog_go.FileInfo
+		if files, err = bundle.entries(); err == nil {
+			result.Files, err = writeFileTree(files, target, opts.Force)
+		}
+	} else {
+		var archive *grpc_catalog_go.FileInfo
+		if archive, err = bundle.tgz(appName + ".tgz"); err == nil {
+			switch {
+			case toStdout:
+				result.Path = "stdout"
+				_, err = os.Stdout.Write(archive.Data)
+			case opts.Extract:
+				result.Files, err = extractApplication(archive, target, opts.Force)
+			default:
+				err = saveApplication(archive, target)
+			}
+		}
+	}
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if lock != nil && expected == "" {
+		log.Debug().Str("key", key).Str("digest", bundle.digest).Str("lockFile", opts.LockFile).Msg("pinning application digest")
+		lock.Applications[key] = bundle.digest
+		err = lock.save(opts.LockFile)
+	}
+	return c.ResultPrinter.PrintResultOrError(result, err)
+}
+
+// loadBundle returns an application from the local cache, or downloads it from the catalog storing
+// it in the cache.
+func (c *Catalog) loadBundle(applicationID string, expected string, tree bool, opts *CacheOptions) (*appBundle, error) {
+	ref, err := c.cacheRef(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := c.cachedBundle(ref, expected, opts)
+	if err != nil || bundle != nil {
+		return bundle, err
+	}
+
+	// Connection
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Call Download
+	bundle, err = c.downloadBundle(grpc_catalog_go.NewCatalogClient(conn), applicationID, tree, progress.NewTracker("pull", applicationID, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	c.storeBundle(ref, bundle)
+	return bundle, nil
+}
+
+// expectedDigest returns the digest that the pulled application must have, taken from the options
+// or from the lock file. Both sources must agree if they are provided.
+func expectedDigest(key string, opts *PullOptions, lock *lockFile) (string, error) {
+	expected := opts.Digest
+	if lock == nil {
+		return expected, nil
+	}
+	pinned, exists := lock.Applications[key]
+	if !exists {
+		return expected, nil
+	}
+	if expected != "" && !strings.EqualFold(expected, pinned) {
+		return "", nerrors.NewInvalidArgumentError("digest %s does not match %s pinned for %s in %s", expected, pinned, key, opts.LockFile)
+	}
+	return pinned, nil
+}
+
+// pullTarget returns the path where the application is written. When the application is not extracted,
+// an output ending with a separator or pointing to an existing directory receives the <appName>.tgz file.
+func pullTarget(appName string, opts *PullOptions) string {
+	if opts.toDirectory() {
+		if opts.Output == "" {
+			return appName
+		}
+		return opts.Output
+	}
+	fileName := appName + ".tgz"
+	if opts.Output == "" {
+		return fileName
+	}
+	if strings.HasSuffix(opts.Output, "/") || strings.HasSuffix(opts.Output, string(os.PathSeparator)) {
+		return filepath.Join(opts.Output, fileName)
+	}
+	if info, err := os.Stat(opts.Output); err == nil && info.IsDir() {
+		return filepath.Join(opts.Output, fileName)
+	}
+	return opts.Output
+}
+
+// checkOverwrite verifies that the tgz file can be written in the target path.
+func checkOverwrite(target string, force bool) error {
+	info, err := os.Lstat(target)
+	if err != nil {
+		return nil
+	}
+	if !info.Mode().IsRegular() {
+		return nerrors.NewFailedPreconditionError("unable to write the application in %s, the path exists and it is not a regular file", target)
+	}
+	if !force {
+		return nerrors.NewAlreadyExistsError("%s already exists, use --force to overwrite it", target)
+	}
+	return nil
+}
+
+// saveApplication writes the tgz received from the catalog creating the parent directories if required.
+func saveApplication(file *grpc_catalog_go.FileInfo, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to create directory for %s", target)
+	}
+	return SaveFile(target, file)
+}
+
+// extractApplication writes the files contained in the tgz received from the catalog in the target
+// directory returning the paths of the files written. The paths of the entries are chosen by the
+// catalog, so they are checked before writing anything.
+func extractApplication(file *grpc_catalog_go.FileInfo, targetDir string, force bool) ([]string, error) {
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to create directory %s", targetDir)
+	}
+	if err := checkExtraction(file.Data, file.Path, targetDir, force); err != nil {
+		return nil, err
+	}
+	entries, err := extractTar(bytes.NewReader(file.Data), file.Path, targetDir, "")
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, filepath.Join(targetDir, filepath.FromSlash(entry)))
+	}
+	return paths, nil
+}
+
+// checkExtraction inspects the entries of an archive so that the target directory is not modified
+// if any of them is refused. Entries are refused if they are not files or directories, if their path
+// is not confined to the target directory, or if they would overwrite an existing file without force.
+func checkExtraction(data []byte, source string, targetDir string, force bool) error {
+	realTarget, err := filepath.EvalSymlinks(targetDir)
+	if err != nil {
+		return nerrors.NewInternalErrorFrom(err, "unable to resolve directory %s", targetDir)
+	}
+	tarReader, closeTar, err := openTar(bytes.NewReader(data), source)
+	if err != nil {
+		return err
+	}
+	defer closeTar()
+
+	var refused []string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nerrors.NewInvalidArgumentError("unable to read %s: %s", source, err.Error())
+		}
+		var reason string
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader:
+			continue
+		case tar.TypeDir, tar.TypeReg, tar.TypeRegA:
+			reason = checkEntry(header.Name, header.Typeflag == tar.TypeDir, realTarget, force)
+		default:
+			reason = "only files and directories are supported"
+		}
+		if reason != "" {
+			refused = append(refused, refusedEntry(header.Name, reason))
+		}
+	}
+	return refusedError(targetDir, refused)
+}
+
+// writeFileTree writes the files received from the catalog in the target directory preserving their
+// relative paths. As in the extraction of archives, the paths are checked before writing anything.
+func writeFileTree(files []*grpc_catalog_go.FileInfo, targetDir string, force bool) ([]string, error) {
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to create directory %s", targetDir)
+	}
+	realTarget, err := filepath.EvalSymlinks(targetDir)
+	if err != nil {
+		return nil, nerrors.NewInternalErrorFrom(err, "unable to resolve directory %s", targetDir)
+	}
+	var refused []string
+	for _, file := range files {
+		if reason := checkEntry(file.Path, false, realTarget, force); reason != "" {
+			refused = append(refused, refusedEntry(file.Path, reason))
+		}
+	}
+	if err := refusedError(targetDir, refused); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		entryPath, err := sanitizeEntryPath(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := createFile(targetDir, entryPath, bytes.NewReader(file.Data)); err != nil {
+			return nil, err
+		}
+		paths = append(paths, filepath.Join(targetDir, filepath.FromSlash(entryPath)))
+	}
+	return paths, nil
+}
+
+// refusedEntry returns the description of an entry that cannot be written.
+func refusedEntry(name string, reason string) string {
+	log.Debug().Str("entry", name).Str("reason", reason).Msg("entry refused")
+	return fmt.Sprintf("%q: %s", name, reason)
+}
+
+// refusedError returns an error listing the refused entries, if any.
+func refusedError(targetDir string, refused []string) error {
+	if len(refused) == 0 {
+		return nil
+	}
+	return nerrors.NewFailedPreconditionError("unable to write the application in %s, %d entries refused:\n%s",
+		targetDir, len(refused), strings.Join(refused, "\n"))
+}
+
+// checkEntry returns the reason why a file or directory received from the catalog cannot be written in
+// the target directory, or an empty string if it can be written.
+func checkEntry(name string, isDir bool, realTarget string, force bool) string {
+	entryPath, err := sanitizeEntryPath(name)
+	if err != nil {
+		return "the path is outside the target directory"
+	}
+	target := filepath.Join(realTarget, filepath.FromSlash(entryPath))
+	// existing links in the target directory must not redirect the entry outside of it
+	if resolved, err := resolveExisting(target); err != nil || !isWithin(realTarget, resolved) {
+		return "the path resolves outside the target directory"
+	}
+	info, err := os.Lstat(target)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case isDir && info.IsDir():
+		return ""
+	case isDir:
+		return "a file with the same name already exists"
+	case !info.Mode().IsRegular():
+		return "a directory or link with the same name already exists"
+	case !force:
+		return "the file already exists, use --force to overwrite it"
+	}
+	return ""
+}
+
+// resolveExisting resolves the symbolic links of the longest existing prefix of a path.
+func resolveExisting(path string) (string, error) {
+	var missing []string
+	current := path
+	for {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", err
+		}
+		missing = append([]string{filepath.Base(current)}, missing...)
+		current = parent
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/napptive/catalog-cli/v2/pkg/config"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Pull tests", func() {
+
+	var targetDir string
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-pull")
+		gomega.Expect(err).To(gomega.Succeed())
+		targetDir = dir
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(targetDir)
+	})
+
+	ginkgo.It("Should choose the output path of the application", func() {
+		gomega.Expect(pullTarget("app", &PullOptions{})).To(gomega.Equal("app.tgz"))
+		gomega.Expect(pullTarget("app", &PullOptions{Output: "out/"})).To(gomega.Equal(filepath.Join("out", "app.tgz")))
+		gomega.Expect(pullTarget("app", &PullOptions{Output: targetDir})).To(gomega.Equal(filepath.Join(targetDir, "app.tgz")))
+		gomega.Expect(pullTarget("app", &PullOptions{Output: "bundle.tgz"})).To(gomega.Equal("bundle.tgz"))
+		gomega.Expect(pullTarget("app", &PullOptions{Extract: true})).To(gomega.Equal("app"))
+		gomega.Expect(pullTarget("app", &PullOptions{Extract: true, Output: targetDir})).To(gomega.Equal(targetDir))
+	})
+
+	ginkgo.It("Should extract the application files", func() {
+		file := &grpc_catalog_go.FileInfo{Path: "app.tgz", Data: createTestArchive(map[string]string{
+			"./metadata.yaml": "kind: ApplicationMetadata",
+			"./app/app.yaml":  "kind: Application",
+		})}
+		paths, err := extractApplication(file, targetDir, false)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(paths).To(gomega.ConsistOf(filepath.Join(targetDir, "metadata.yaml"), filepath.Join(targetDir, "app", "app.yaml")))
+	})
+
+	ginkgo.It("Should refuse entries outside the target directory without writing any file", func() {
+		file := &grpc_catalog_go.FileInfo{Path: "app.tgz", Data: createTestArchive(map[string]string{
+			"./metadata.yaml":  "kind: ApplicationMetadata",
+			"../../.bashrc":    "echo",
+			"/etc/cron.d/evil": "* * * * *",
+		})}
+		_, err := extractApplication(file, targetDir, false)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("2 entries refused"))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("../../.bashrc"))
+		_, err = os.Stat(filepath.Join(targetDir, "metadata.yaml"))
+		gomega.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("Should refuse entries redirected outside the target directory by a link", func() {
+		outside, err := os.MkdirTemp("", "catalog-outside")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(outside)
+		gomega.Expect(os.Symlink(outside, filepath.Join(targetDir, "app"))).To(gomega.Succeed())
+
+		file := &grpc_catalog_go.FileInfo{Path: "app.tgz", Data: createTestArchive(map[string]string{"./app/app.yaml": "kind: Application"})}
+		_, err = extractApplication(file, targetDir, false)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		_, err = os.Stat(filepath.Join(outside, "app.yaml"))
+		gomega.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("Should write uncompressed files preserving their relative paths", func() {
+		files := []*grpc_catalog_go.FileInfo{
+			{Path: "./metadata.yaml", Data: []byte("kind: ApplicationMetadata")},
+			{Path: "./app/app.yaml", Data: []byte("kind: Application")},
+		}
+		paths, err := writeFileTree(files, targetDir, false)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(paths).To(gomega.ConsistOf(filepath.Join(targetDir, "metadata.yaml"), filepath.Join(targetDir, "app", "app.yaml")))
+		content, err := os.ReadFile(filepath.Join(targetDir, "app", "app.yaml"))
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("kind: Application"))
+
+		files = append(files, &grpc_catalog_go.FileInfo{Path: "../evil.yaml", Data: []byte("evil")})
+		_, err = writeFileTree(files, targetDir, true)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("1 entries refused"))
+	})
+
+	ginkgo.It("Should only overwrite existing files with force", func() {
+		existing := filepath.Join(targetDir, "metadata.yaml")
+		gomega.Expect(os.WriteFile(existing, []byte("local"), 0644)).To(gomega.Succeed())
+		file := &grpc_catalog_go.FileInfo{Path: "app.tgz", Data: createTestArchive(map[string]string{"./metadata.yaml": "remote"})}
+
+		_, err := extractApplication(file, targetDir, false)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		content, err := os.ReadFile(existing)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("local"))
+
+		_, err = extractApplication(file, targetDir, true)
+		gomega.Expect(err).To(gomega.Succeed())
+		content, err = os.ReadFile(existing)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(string(content)).To(gomega.Equal("remote"))
+
+		gomega.Expect(checkOverwrite(existing, false)).NotTo(gomega.Succeed())
+		gomega.Expect(checkOverwrite(existing, true)).To(gomega.Succeed())
+		gomega.Expect(checkOverwrite(targetDir, true)).NotTo(gomega.Succeed())
+	})
+	ginkgo.Context("Pulling to the standard output", func() {
+
+		var catalog *Catalog
+		var stdout, stderr *os.File
+		var archive []byte
+		var digest string
+
+		ginkgo.BeforeEach(func() {
+			cfg := &config.Config{
+				ConnectionConfig: config.ConnectionConfig{CatalogAddress: "catalog", CatalogPort: 7060},
+				PrinterType:      "json",
+				CacheDir:         filepath.Join(targetDir, "cache"),
+			}
+			catalog = &Catalog{cfg: cfg, AuthToken: config.NewAuthToken(cfg)}
+			archive = createTestArchive(map[string]string{"./metadata.yaml": "kind: ApplicationMetadata"})
+			var err error
+			digest, err = archiveDigest(archive, "app.tgz")
+			gomega.Expect(err).To(gomega.Succeed())
+			ref, err := catalog.cacheRef("ns/app:1.0")
+			gomega.Expect(err).To(gomega.Succeed())
+			catalog.storeBundle(ref, &appBundle{archive: &grpc_catalog_go.FileInfo{Path: "app.tgz", Data: archive}, digest: digest})
+
+			stdout, err = os.Create(filepath.Join(targetDir, "stdout"))
+			gomega.Expect(err).To(gomega.Succeed())
+			stderr, err = os.Create(filepath.Join(targetDir, "stderr"))
+			gomega.Expect(err).To(gomega.Succeed())
+		})
+
+		ginkgo.AfterEach(func() {
+			stdout.Close()
+			stderr.Close()
+		})
+
+		// pullToStdout pulls an application with the standard output and error redirected to files.
+		pullToStdout := func(opts *PullOptions) ([]byte, []byte, error) {
+			originalStdout, originalStderr := os.Stdout, os.Stderr
+			os.Stdout, os.Stderr = stdout, stderr
+			err := catalog.Pull("ns/app:1.0", opts)
+			os.Stdout, os.Stderr = originalStdout, originalStderr
+			outContent, readErr := os.ReadFile(stdout.Name())
+			gomega.Expect(readErr).To(gomega.Succeed())
+			errContent, readErr := os.ReadFile(stderr.Name())
+			gomega.Expect(readErr).To(gomega.Succeed())
+			return outContent, errContent, err
+		}
+
+		ginkgo.It("Should only write the archive on stdout and the result on stderr", func() {
+			outContent, errContent, err := pullToStdout(&PullOptions{Output: StdoutPath, CacheOptions: CacheOptions{Offline: true}})
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(bytes.Equal(outContent, archive)).To(gomega.BeTrue())
+
+			result := &entities.PullResult{}
+			gomega.Expect(json.Unmarshal(errContent, result)).To(gomega.Succeed())
+			gomega.Expect(result.ApplicationID).To(gomega.Equal("ns/app:1.0"))
+			gomega.Expect(result.Path).To(gomega.Equal("stdout"))
+			gomega.Expect(result.Digest).To(gomega.Equal(digest))
+		})
+
+		ginkgo.It("Should not write anything on stdout if the pull fails", func() {
+			wrong := DigestPrefix + "0000000000000000000000000000000000000000000000000000000000000000"
+			outContent, _, err := pullToStdout(&PullOptions{Output: StdoutPath, Digest: wrong, CacheOptions: CacheOptions{Offline: true}})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring("digest mismatch"))
+			gomega.Expect(outContent).To(gomega.BeEmpty())
+
+			outContent, _, err = pullToStdout(&PullOptions{Output: StdoutPath, Format: PullFormatTree})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(outContent).To(gomega.BeEmpty())
+		})
+	})
+})
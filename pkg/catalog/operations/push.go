@@ -0,0 +1,305 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/progress"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_common_go "github.com/napptive/grpc-catalog-common-go"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// PushOptions with the options that modify the behavior of the push operation.
+type PushOptions struct {
+	WalkOptions
+	// Private indicates that the application is only visible to the owner.
+	Private bool
+	// MaxMessageSize with the maximum size in bytes of the messages accepted by the catalog.
+	MaxMessageSize int
+	// Force pushes the application even if the catalog already contains the same content.
+	Force bool
+	// DryRun prints the files that would be pushed without contacting the catalog.
+	DryRun bool
+	// Tags with additional tags under which the application is pushed.
+	Tags []string
+	// GitRef with the git commit, branch or tag from which the application files are read.
+	GitRef string
+}
+
+// Push adds a new application to catalog. The path may be a directory, a .tgz, .tar or .zip archive,
+// or - to read a tar stream from the standard input.
+func (c *Catalog) Push(applicationID string, appPath string, opts *PushOptions) error {
+	log.Debug().Str("applicationID", applicationID).Str("path", appPath).Strs("tags", opts.Tags).Msg("Push received!")
+
+	var path string
+	var cleanup func()
+	var err error
+	if opts.GitRef != "" {
+		applicationID = defaultTagFromGit(applicationID, appPath, opts.GitRef)
+		path, cleanup, err = prepareGitApplication(appPath, opts.GitRef, opts.Symlinks)
+	} else {
+		path, cleanup, err = prepareApplicationPath(appPath)
+	}
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	defer cleanup()
+
+	targets, err := pushTargets(applicationID, opts.Tags)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+
+	// Read the path and compose the AddCatalogRequest
+	names, err := c.readApp(path, opts.WalkOptions)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	log.Debug().Interface("names", names).Msg("Files found")
+
+	// Check the files before opening the stream so the catalog does not receive a partial application
+	files, err := statAppFiles(path, names)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	for _, target := range targets {
+		if err := checkFileSizes(target, files, opts.MaxMessageSize); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+	digest, err := localDigest(path, files)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	log.Debug().Str("digest", digest).Msg("local digest")
+
+	if opts.DryRun {
+		plan, err := c.pushPlan(targets, digest, files, opts)
+		return c.ResultPrinter.PrintResultOrError(plan, err)
+	}
+
+	// Send the request
+	// All the targets share the catalog as they only differ in the tag
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, targets[0])
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	defer conn.Close()
+	client := grpc_catalog_go.NewCatalogClient(conn)
+
+	if len(opts.Tags) == 0 {
+		reply, _, err := c.pushApplication(client, targets[0], path, files, digest, opts)
+		return c.ResultPrinter.PrintResultOrError(reply, err)
+	}
+
+	summary := &entities.PushSummary{Digest: digest}
+	numFailed := 0
+	for _, target := range targets {
+		result := &entities.PushResult{ApplicationID: target, Status: entities.PushSuccess}
+		reply, unchanged, err := c.pushApplication(client, target, path, files, digest, opts)
+		if err != nil {
+			log.Debug().Err(err).Str("applicationID", target).Msg("push failed")
+			result.Status = entities.PushFailed
+			result.Info = err.Error()
+			numFailed++
+		} else {
+			result.Info = reply.UserInfo
+			if unchanged {
+				result.Status = entities.PushUnchanged
+			}
+		}
+		summary.Results = append(summary.Results, result)
+	}
+	if err := c.ResultPrinter.PrintResultOrError(summary, nil); err != nil {
+		return err
+	}
+	if numFailed > 0 {
+		return nerrors.NewInternalError("%d of %d tags could not be pushed", numFailed, len(targets))
+	}
+	return nil
+}
+
+// pushTargets returns the list of application identifiers that must be pushed. The tag of the
+// application identifier, if any, is followed by the additional tags.
+func pushTargets(applicationID string, tags []string) ([]string, error) {
+	if _, _, _, _, err := DecomposeApplicationName(applicationID); err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return []string{applicationID}, nil
+	}
+	base := applicationID
+	var result []string
+	if hasTag(applicationID) {
+		base = applicationID[:strings.LastIndex(applicationID, ":")]
+		result = append(result, applicationID)
+	}
+	for _, tag := range tags {
+		if tag == "" || strings.ContainsAny(tag, ":/") {
+			return nil, nerrors.NewInvalidArgumentError("invalid tag %q", tag)
+		}
+		target := fmt.Sprintf("%s:%s", base, tag)
+		if !contains(result, target) {
+			result = append(result, target)
+		}
+	}
+	return result, nil
+}
+
+// hasTag checks if an application identifier contains a tag. The catalog URL may contain a port.
+func hasTag(applicationID string) bool {
+	return strings.LastIndex(applicationID, ":") > strings.LastIndex(applicationID, "/")
+}
+
+// contains checks if a list of strings contains a given value.
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// pushApplication uploads the files under an application identifier. The upload is skipped if the
+// catalog already contains the same content with the same visibility, returning true.
+func (c *Catalog) pushApplication(client grpc_catalog_go.CatalogClient, applicationID string, path string, files []appFile, digest string, opts *PushOptions) (*grpc_catalog_common_go.OpResponse, bool, error) {
+	if !opts.Force && c.isUnchanged(client, applicationID, digest, opts.Private) {
+		return &grpc_catalog_common_go.OpResponse{
+			Status:     grpc_catalog_common_go.OpStatus_SUCCESS,
+			StatusName: grpc_catalog_common_go.OpStatus_SUCCESS.String(),
+			UserInfo:   fmt.Sprintf("application %s unchanged (%s), push skipped", applicationID, digest),
+		}, true, nil
+	}
+
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+
+	// Get response and print result
+	stream, err := client.Add(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var totalSize int64
+	for _, file := range files {
+		totalSize += file.size
+	}
+	tracker := progress.NewTracker("push", applicationID, len(files), totalSize)
+	defer tracker.Done()
+	// Files are read one at a time so memory usage does not depend on the size of the application
+	for _, file := range files {
+		data, err := readAppFile(path, file)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := stream.Send(&grpc_catalog_go.AddApplicationRequest{
+			ApplicationId: applicationID,
+			Private:       opts.Private,
+			File: &grpc_catalog_go.FileInfo{
+				Path: file.name,
+				Data: data,
+			},
+		}); err != nil {
+			if err == io.EOF {
+				// The server closed the stream, the cause is returned by CloseAndRecv
+				_, err = stream.CloseAndRecv()
+			}
+			return nil, false, sendError(file, err)
+		}
+		tracker.Add(1, file.size)
+	}
+
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, false, err
+	}
+	log.Debug().Interface("reply", reply).Msg("Application sent")
+	if ref, err := c.cacheRef(applicationID); err == nil {
+		c.storePushedDigest(ref, digest)
+	}
+	return reply, false, nil
+}
+
+// pushPlan describes the files that would be pushed and the target applications.
+func (c *Catalog) pushPlan(targets []string, digest string, files []appFile, opts *PushOptions) (*entities.PushPlan, error) {
+	catalogURL, err := connection.GetURL(&c.cfg.ConnectionConfig, targets[0])
+	if err != nil {
+		return nil, err
+	}
+	visibility := "Public"
+	if opts.Private {
+		visibility = "Private"
+	}
+	plan := &entities.PushPlan{
+		CatalogURL: catalogURL,
+		Visibility: visibility,
+		Digest:     digest,
+		NumFiles:   len(files),
+	}
+	for _, target := range targets {
+		_, namespace, appName, tag, err := DecomposeApplicationName(target)
+		if err != nil {
+			return nil, err
+		}
+		plan.ApplicationIDs = append(plan.ApplicationIDs, fmt.Sprintf("%s/%s:%s", namespace, appName, tag))
+	}
+	for _, file := range files {
+		plan.TotalSize += file.size
+		plan.Files = append(plan.Files, &entities.BundleFile{Path: file.name, Size: file.size})
+	}
+	return plan, nil
+}
+
+// isUnchanged checks if the catalog already contains an application with the given digest and
+// visibility. The catalog does not return the digest of an application, so the remote files are
+// downloaded to compute it. A different digest recorded in the local cache when the tag was last
+// pulled or pushed avoids the download. Any error retrieving the application is considered a change
+// so that it is pushed.
+func (c *Catalog) isUnchanged(client grpc_catalog_go.CatalogClient, applicationID string, digest string, private bool) bool {
+	if ref, err := c.cacheRef(applicationID); err == nil {
+		if known := c.knownDigest(ref); known != "" && known != digest {
+			log.Debug().Str("applicationID", applicationID).Str("known", known).Msg("content changed, pushing it")
+			return false
+		}
+	}
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+	info, err := client.Info(ctx, &grpc_catalog_go.InfoApplicationRequest{ApplicationId: applicationID})
+	if err != nil {
+		log.Debug().Err(err).Str("applicationID", applicationID).Msg("unable to retrieve remote application, pushing it")
+		return false
+	}
+	if info.Private != private {
+		log.Debug().Str("applicationID", applicationID).Bool("private", private).Msg("visibility changed, pushing it")
+		return false
+	}
+	files, err := c.downloadApplication(ctx, client, applicationID, false, nil)
+	if err != nil {
+		log.Debug().Err(err).Str("applicationID", applicationID).Msg("unable to download remote application, pushing it")
+		return false
+	}
+	remoteDigest := filesDigest(files)
+	log.Debug().Str("applicationID", applicationID).Str("digest", remoteDigest).Msg("remote digest")
+	return remoteDigest == digest
+}
@@ -0,0 +1,175 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/napptive/catalog-cli/v2/pkg/config"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Push tests", func() {
+
+	var cacheDir string
+	var catalog *Catalog
+	var client *fakeCatalogClient
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-push")
+		gomega.Expect(err).To(gomega.Succeed())
+		cacheDir = dir
+		cfg := &config.Config{
+			ConnectionConfig: config.ConnectionConfig{CatalogAddress: "catalog", CatalogPort: 7060},
+			CacheDir:         cacheDir,
+		}
+		catalog = &Catalog{cfg: cfg, AuthToken: config.NewAuthToken(cfg)}
+		client = &fakeCatalogClient{}
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(cacheDir)
+	})
+
+	ginkgo.Context("Skipping unchanged applications", func() {
+
+		var digest string
+
+		ginkgo.BeforeEach(func() {
+			client.files = map[string]string{"./metadata.yaml": "kind: ApplicationMetadata", "./app.yaml": "kind: Application"}
+			digest = filesDigest([]*grpc_catalog_go.FileInfo{
+				{Path: "app.yaml", Data: []byte("kind: Application")},
+				{Path: "metadata.yaml", Data: []byte("kind: ApplicationMetadata")},
+			})
+		})
+
+		storePushedDigest := func(applicationID string, digest string) {
+			ref, err := catalog.cacheRef(applicationID)
+			gomega.Expect(err).To(gomega.Succeed())
+			catalog.storePushedDigest(ref, digest)
+		}
+
+		ginkgo.It("Should skip an application with the same remote digest and visibility", func() {
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", digest, true)).To(gomega.BeTrue())
+			gomega.Expect(catalog.isUnchanged(client, "ns/app", digest, true)).To(gomega.BeTrue())
+			storePushedDigest("ns/app:1.0", digest)
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", digest, true)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("Should push an application if the visibility changes", func() {
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", digest, false)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("Should push an application if the remote digest is different", func() {
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", "sha256:abc", true)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("Should push an application if the remote content differs from the cached digest", func() {
+			storePushedDigest("ns/app:1.0", digest)
+			client.files = map[string]string{"./metadata.yaml": "kind: ApplicationMetadata"}
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", digest, true)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("Should push an application without downloading it if the cached digest is different", func() {
+			storePushedDigest("ns/app:1.0", "sha256:abc")
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", digest, true)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("Should push an application that cannot be downloaded", func() {
+			client.failing = map[string]bool{"ns/app:1.0": true}
+			gomega.Expect(catalog.isUnchanged(client, "ns/app:1.0", digest, true)).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.It("Should report the targets, digest and sizes in a dry run", func() {
+		appDir, err := os.MkdirTemp("", "catalog-push-app")
+		gomega.Expect(err).To(gomega.Succeed())
+		defer os.RemoveAll(appDir)
+		gomega.Expect(os.MkdirAll(filepath.Join(appDir, "app"), 0755)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "metadata.yaml"), []byte("metadata"), 0644)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "app", "app.yaml"), []byte("application"), 0644)).To(gomega.Succeed())
+
+		names, err := catalog.readApp(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		files, err := statAppFiles(appDir, names)
+		gomega.Expect(err).To(gomega.Succeed())
+		digest, err := localDigest(appDir, files)
+		gomega.Expect(err).To(gomega.Succeed())
+
+		targets, err := pushTargets("ns/app:1.0", []string{"stable"})
+		gomega.Expect(err).To(gomega.Succeed())
+
+		plan, err := catalog.pushPlan(targets, digest, files, &PushOptions{Private: true})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(plan.CatalogURL).To(gomega.Equal("catalog:7060"))
+		gomega.Expect(plan.ApplicationIDs).To(gomega.Equal([]string{"ns/app:1.0", "ns/app:stable"}))
+		gomega.Expect(plan.Visibility).To(gomega.Equal("Private"))
+		gomega.Expect(plan.Digest).To(gomega.Equal(digest))
+		gomega.Expect(plan.Digest).To(gomega.HavePrefix("sha256:"))
+		gomega.Expect(plan.NumFiles).To(gomega.Equal(2))
+		gomega.Expect(plan.TotalSize).To(gomega.Equal(int64(len("metadata") + len("application"))))
+		gomega.Expect(plan.Files).To(gomega.HaveLen(2))
+		sizes := map[string]int64{}
+		for _, file := range plan.Files {
+			sizes[filepath.Base(file.Path)] = file.Size
+		}
+		gomega.Expect(sizes).To(gomega.Equal(map[string]int64{"metadata.yaml": 8, "app.yaml": 11}))
+
+		plan, err = catalog.pushPlan([]string{"ns/app"}, digest, files, &PushOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(plan.ApplicationIDs).To(gomega.Equal([]string{"ns/app:latest"}))
+		gomega.Expect(plan.Visibility).To(gomega.Equal("Public"))
+	})
+
+	ginkgo.Context("Resolving the push targets", func() {
+
+		ginkgo.It("Should push the application identifier if no tag is given", func() {
+			targets, err := pushTargets("ns/app:1.0", nil)
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(targets).To(gomega.Equal([]string{"ns/app:1.0"}))
+		})
+
+		ginkgo.It("Should push every repeated tag once", func() {
+			targets, err := pushTargets("ns/app", []string{"1.0", "stable", "1.0", "latest"})
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(targets).To(gomega.Equal([]string{"ns/app:1.0", "ns/app:stable", "ns/app:latest"}))
+		})
+
+		ginkgo.It("Should push a tag given in the identifier and in the tags once", func() {
+			targets, err := pushTargets("ns/app:1.0", []string{"stable", "1.0"})
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(targets).To(gomega.Equal([]string{"ns/app:1.0", "ns/app:stable"}))
+
+			targets, err = pushTargets("catalog.example.com:7060/ns/app:1.0", []string{"1.0", "2.0"})
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(targets).To(gomega.Equal([]string{"catalog.example.com:7060/ns/app:1.0", "catalog.example.com:7060/ns/app:2.0"}))
+		})
+
+		ginkgo.It("Should reject invalid tags", func() {
+			for _, tag := range []string{"", "1.0:beta", "stable/1"} {
+				_, err := pushTargets("ns/app", []string{"1.0", tag})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(err.Error()).To(gomega.ContainSubstring("invalid tag"))
+			}
+			_, err := pushTargets("app", []string{"1.0"})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,169 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/prompt"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+// RemoveOptions with the options of the remove operation.
+type RemoveOptions struct {
+	BulkRemoveOptions
+	BackupOptions
+	// Yes removes the applications without asking for confirmation.
+	Yes bool
+	// DryRun prints the applications that would be removed without removing them.
+	DryRun bool
+}
+
+// Remove deletes an application from catalog repository. If the standard input is a terminal, the
+// application tags that will be removed are shown and the user must confirm the operation unless
+// the Yes option is set. With the Backup option, the application tags are saved in the trash before
+// removing them.
+func (c *Catalog) Remove(applicationID string, opts *RemoveOptions) error {
+	log.Debug().Str("applicationID", applicationID).Bool("dryRun", opts.DryRun).Msg("Remove received!")
+	if !hasTag(applicationID) {
+		log.Warn().Str("application", applicationID).Msg("no tag given, all the tags of the application will be removed")
+	}
+
+	// Connection
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, applicationID)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s:%d",
+			c.cfg.CatalogAddress, c.cfg.CatalogPort))
+	}
+	defer conn.Close()
+
+	// Client
+	client := grpc_catalog_go.NewCatalogClient(conn)
+
+	confirm := !opts.Yes && prompt.IsInteractive()
+	var plan *entities.RemovePlan
+	if opts.DryRun || confirm || opts.Backup {
+		plan, err = c.removePlan(client, applicationID)
+		if err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+	if opts.DryRun || confirm {
+		if err := c.ResultPrinter.PrintResultOrError(plan, nil); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return nil
+		}
+		question := fmt.Sprintf("Remove %d tags of %s from %s?", len(plan.Targets), applicationID, plan.CatalogURL)
+		if !plan.AllTags {
+			question = fmt.Sprintf("Remove %s from %s?", applicationID, plan.CatalogURL)
+		}
+		confirmed, err := prompt.Confirm(os.Stdin, os.Stderr, question)
+		if err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+		if !confirmed {
+			return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewCanceledError("remove canceled, %s has not been removed", applicationID))
+		}
+	}
+	if opts.Backup {
+		if _, err := c.backupApplications(client, removeOperation, plan.CatalogURL, plan.Targets); err != nil {
+			return c.ResultPrinter.PrintResultOrError(nil, err)
+		}
+	}
+
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+
+	// Call Delete op
+	response, err := client.Remove(ctx, &grpc_catalog_go.RemoveApplicationRequest{ApplicationId: applicationID})
+	return c.ResultPrinter.PrintResultOrError(response, err)
+}
+
+// removePlan returns the application tags affected by the removal of an application. All the tags of
+// the application are affected if the application identifier has no tag.
+func (c *Catalog) removePlan(client grpc_catalog_go.CatalogClient, applicationID string) (*entities.RemovePlan, error) {
+	catalogURL, err := connection.GetURL(&c.cfg.ConnectionConfig, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	_, namespace, appName, tag, err := DecomposeApplicationName(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &entities.RemovePlan{CatalogURL: catalogURL, AllTags: !hasTag(applicationID), Targets: make([]*entities.RemoveTarget, 0)}
+
+	ctx, cancel := c.AuthToken.GetContext()
+	defer cancel()
+	if !plan.AllTags {
+		info, err := client.Info(ctx, &grpc_catalog_go.InfoApplicationRequest{ApplicationId: applicationID})
+		if err != nil {
+			return nil, nerrors.FromGRPC(err)
+		}
+		plan.Targets = append(plan.Targets, &entities.RemoveTarget{
+			ApplicationID: fmt.Sprintf("%s/%s:%s", namespace, appName, tag),
+			Name:          info.GetMetadata().GetName(),
+			Private:       info.Private,
+		})
+		return plan, nil
+	}
+
+	app, err := findApplication(ctx, client, namespace, appName)
+	if err != nil {
+		return nil, err
+	}
+	if app != nil {
+		tags := make([]string, 0, len(app.TagMetadataName))
+		for appTag := range app.TagMetadataName {
+			tags = append(tags, appTag)
+		}
+		sort.Strings(tags)
+		for _, appTag := range tags {
+			plan.Targets = append(plan.Targets, &entities.RemoveTarget{
+				ApplicationID: fmt.Sprintf("%s/%s:%s", namespace, appName, appTag),
+				Name:          app.TagMetadataName[appTag],
+				Private:       app.Private,
+			})
+		}
+	}
+	if len(plan.Targets) == 0 {
+		return nil, nerrors.NewNotFoundError("application %s not found in %s", applicationID, catalogURL)
+	}
+	return plan, nil
+}
+
+// findApplication returns the summary of an application, with all its tags, or nil if it does not exist.
+func findApplication(ctx context.Context, client grpc_catalog_go.CatalogClient, namespace string, appName string) (*grpc_catalog_go.ApplicationSummary, error) {
+	response, err := client.List(ctx, &grpc_catalog_go.ListApplicationsRequest{Namespace: namespace})
+	if err != nil {
+		return nil, nerrors.FromGRPC(err)
+	}
+	for _, app := range response.Applications {
+		if app.Namespace == namespace && app.ApplicationName == appName {
+			return app, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,205 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/napptive/catalog-cli/v2/pkg/config"
+	grpc_catalog_common_go "github.com/napptive/grpc-catalog-common-go"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCatalogClient with a catalog client that answers Info, List and Download with fixed responses,
+// and records the removed and added applications.
+type fakeCatalogClient struct {
+	grpc_catalog_go.CatalogClient
+	// applications returned by List.
+	applications []*grpc_catalog_go.ApplicationSummary
+	// failing with the applications that cannot be removed or downloaded.
+	failing map[string]bool
+	// files with the content of the downloaded applications, a metadata file if empty.
+	files map[string]string
+	// removed with the applications removed.
+	removed sync.Map
+	// added with the requests received by Add.
+	added []*grpc_catalog_go.AddApplicationRequest
+}
+
+// Info returns the information of an application.
+func (f *fakeCatalogClient) Info(ctx context.Context, in *grpc_catalog_go.InfoApplicationRequest, opts ...grpc.CallOption) (*grpc_catalog_go.InfoApplicationResponse, error) {
+	return &grpc_catalog_go.InfoApplicationResponse{Metadata: &grpc_catalog_go.ApplicationMetadata{Name: "My app"}, Private: true}, nil
+}
+
+// List returns the applications of the namespace.
+func (f *fakeCatalogClient) List(ctx context.Context, in *grpc_catalog_go.ListApplicationsRequest, opts ...grpc.CallOption) (*grpc_catalog_go.ApplicationList, error) {
+	result := &grpc_catalog_go.ApplicationList{}
+	for _, app := range f.applications {
+		if app.Namespace == in.Namespace {
+			result.Applications = append(result.Applications, app)
+		}
+	}
+	return result, nil
+}
+
+// Remove deletes an application unless it is configured to fail.
+func (f *fakeCatalogClient) Remove(ctx context.Context, in *grpc_catalog_go.RemoveApplicationRequest, opts ...grpc.CallOption) (*grpc_catalog_common_go.OpResponse, error) {
+	if f.failing[in.ApplicationId] {
+		return nil, status.Error(codes.PermissionDenied, "not allowed")
+	}
+	f.removed.Store(in.ApplicationId, true)
+	return &grpc_catalog_common_go.OpResponse{}, nil
+}
+
+// Download returns a tgz file with the metadata of the application unless it is configured to fail.
+func (f *fakeCatalogClient) Download(ctx context.Context, in *grpc_catalog_go.DownloadApplicationRequest, opts ...grpc.CallOption) (grpc_catalog_go.Catalog_DownloadClient, error) {
+	if f.failing[in.ApplicationId] {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	files := f.files
+	if len(files) == 0 {
+		files = map[string]string{"./metadata.yaml": "kind: ApplicationMetadata"}
+	}
+	if !in.Compressed {
+		result := make([]*grpc_catalog_go.FileInfo, 0, len(files))
+		for path, content := range files {
+			result = append(result, &grpc_catalog_go.FileInfo{Path: path, Data: []byte(content)})
+		}
+		return &fakeDownloadClient{files: result}, nil
+	}
+	return &fakeDownloadClient{files: []*grpc_catalog_go.FileInfo{{Path: "app.tgz", Data: createTestArchive(files)}}}, nil
+}
+
+// Add returns a stream recording the files of the application.
+func (f *fakeCatalogClient) Add(ctx context.Context, opts ...grpc.CallOption) (grpc_catalog_go.Catalog_AddClient, error) {
+	return &fakeAddClient{catalog: f}, nil
+}
+
+// fakeAddClient with an upload stream that records the received requests in the catalog client.
+type fakeAddClient struct {
+	grpc.ClientStream
+	// catalog with the client recording the requests.
+	catalog *fakeCatalogClient
+}
+
+// Send records a request.
+func (f *fakeAddClient) Send(request *grpc_catalog_go.AddApplicationRequest) error {
+	f.catalog.added = append(f.catalog.added, request)
+	return nil
+}
+
+// CloseAndRecv returns a successful response.
+func (f *fakeAddClient) CloseAndRecv() (*grpc_catalog_common_go.OpResponse, error) {
+	return &grpc_catalog_common_go.OpResponse{Status: grpc_catalog_common_go.OpStatus_SUCCESS, UserInfo: "application added"}, nil
+}
+
+// fakeDownloadClient with a download stream that returns a fixed list of files.
+type fakeDownloadClient struct {
+	grpc.ClientStream
+	// files pending to be received.
+	files []*grpc_catalog_go.FileInfo
+}
+
+// Recv returns the next file of the stream.
+func (f *fakeDownloadClient) Recv() (*grpc_catalog_go.FileInfo, error) {
+	if len(f.files) == 0 {
+		return nil, io.EOF
+	}
+	file := f.files[0]
+	f.files = f.files[1:]
+	return file, nil
+}
+
+// CloseSend closes the stream.
+func (f *fakeDownloadClient) CloseSend() error {
+	return nil
+}
+
+var _ = ginkgo.Describe("Remove tests", func() {
+
+	var catalog *Catalog
+	var client *fakeCatalogClient
+
+	ginkgo.BeforeEach(func() {
+		cfg := &config.Config{ConnectionConfig: config.ConnectionConfig{CatalogAddress: "catalog", CatalogPort: 7060}}
+		catalog = &Catalog{cfg: cfg, AuthToken: config.NewAuthToken(cfg)}
+		client = &fakeCatalogClient{applications: []*grpc_catalog_go.ApplicationSummary{
+			{Namespace: "ns", ApplicationName: "app", TagMetadataName: map[string]string{"1.0": "My app", "latest": "My app"}},
+			{Namespace: "ns", ApplicationName: "other", TagMetadataName: map[string]string{"latest": "Other"}},
+		}}
+	})
+
+	ginkgo.It("Should show the removed tag", func() {
+		plan, err := catalog.removePlan(client, "ns/app:1.0")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(plan.CatalogURL).To(gomega.Equal("catalog:7060"))
+		gomega.Expect(plan.AllTags).To(gomega.BeFalse())
+		gomega.Expect(plan.Targets).To(gomega.HaveLen(1))
+		gomega.Expect(plan.Targets[0].ApplicationID).To(gomega.Equal("ns/app:1.0"))
+		gomega.Expect(plan.Targets[0].Name).To(gomega.Equal("My app"))
+		gomega.Expect(plan.Targets[0].Private).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("Should show all the tags if no tag is given", func() {
+		plan, err := catalog.removePlan(client, "ns/app")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(plan.AllTags).To(gomega.BeTrue())
+		gomega.Expect(plan.Targets).To(gomega.HaveLen(2))
+		gomega.Expect(plan.Targets[0].ApplicationID).To(gomega.Equal("ns/app:1.0"))
+		gomega.Expect(plan.Targets[1].ApplicationID).To(gomega.Equal("ns/app:latest"))
+
+		_, err = catalog.removePlan(client, "ns/missing")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("Should match the application tags of a namespace", func() {
+		appPattern, tagPattern, err := (&BulkRemoveOptions{Match: "app"}).matchPatterns()
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(matchingTags(client.applications, "ns", appPattern, tagPattern)).To(gomega.HaveLen(2))
+
+		appPattern, tagPattern, err = (&BulkRemoveOptions{Match: "*:latest"}).matchPatterns()
+		gomega.Expect(err).To(gomega.Succeed())
+		targets := matchingTags(client.applications, "ns", appPattern, tagPattern)
+		gomega.Expect(targets).To(gomega.HaveLen(2))
+		gomega.Expect(targets[0].ApplicationID).To(gomega.Equal("ns/app:latest"))
+		gomega.Expect(targets[1].ApplicationID).To(gomega.Equal("ns/other:latest"))
+
+		gomega.Expect(matchingTags(client.applications, "other", "*", "*")).To(gomega.BeEmpty())
+		_, _, err = (&BulkRemoveOptions{Match: "app:"}).matchPatterns()
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("Should report the result of every removal", func() {
+		client.failing = map[string]bool{"ns/app:1.0": true}
+		targets := []*entities.RemoveTarget{{ApplicationID: "ns/app:1.0"}, {ApplicationID: "ns/app:latest"}, {ApplicationID: "ns/other:latest"}}
+		summary := catalog.runBulkRemove(client, targets, 2)
+		gomega.Expect(summary.NumSucceeded).To(gomega.Equal(2))
+		gomega.Expect(summary.NumFailed).To(gomega.Equal(1))
+		gomega.Expect(summary.Results[0].Status).To(gomega.Equal(entities.RemoveFailed))
+		gomega.Expect(summary.Results[0].Info).To(gomega.ContainSubstring("not allowed"))
+		_, removed := client.removed.Load("ns/other:latest")
+		gomega.Expect(removed).To(gomega.BeTrue())
+	})
+})
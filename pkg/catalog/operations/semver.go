@@ -0,0 +1,134 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semanticVersion with the components of a semantic version. Missing minor and patch numbers are
+// considered 0 so that tags such as 1.2 are also accepted.
+type semanticVersion struct {
+	// major version.
+	major int
+	// minor version.
+	minor int
+	// patch version.
+	patch int
+	// prerelease identifiers, if any.
+	prerelease []string
+}
+
+// parseSemanticVersion parses a tag with the format [v]MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD].
+func parseSemanticVersion(tag string) (*semanticVersion, bool) {
+	value := strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+	if index := strings.Index(value, "+"); index != -1 {
+		value = value[:index]
+	}
+	result := &semanticVersion{}
+	if index := strings.Index(value, "-"); index != -1 {
+		if index == len(value)-1 {
+			return nil, false
+		}
+		result.prerelease = strings.Split(value[index+1:], ".")
+		value = value[:index]
+	}
+	components := strings.Split(value, ".")
+	if len(components) > 3 {
+		return nil, false
+	}
+	numbers := []*int{&result.major, &result.minor, &result.patch}
+	for i, component := range components {
+		number, err := strconv.Atoi(component)
+		if err != nil || number < 0 || component == "" || (len(component) > 1 && component[0] == '0') {
+			return nil, false
+		}
+		*numbers[i] = number
+	}
+	return result, true
+}
+
+// compare returns -1, 0 or 1 if the version is lower, equal or greater than the other following the
+// precedence rules of semantic versioning.
+func (v *semanticVersion) compare(other *semanticVersion) int {
+	for _, pair := range [][2]int{{v.major, other.major}, {v.minor, other.minor}, {v.patch, other.patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+	// a version without prerelease has a higher precedence
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if result := comparePrerelease(v.prerelease[i], other.prerelease[i]); result != 0 {
+			return result
+		}
+	}
+	return compareInts(len(v.prerelease), len(other.prerelease))
+}
+
+// comparePrerelease compares two prerelease identifiers. Numeric identifiers are compared numerically
+// and have lower precedence than alphanumeric ones.
+func comparePrerelease(a string, b string) int {
+	numberA, errA := strconv.Atoi(a)
+	numberB, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(numberA, numberB)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInts returns -1, 0 or 1 if a is lower, equal or greater than b.
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareTags orders two tags using semantic versioning if both are versions. Tags that are not
+// versions are compared lexically and are considered older than any version.
+func compareTags(a string, b string) int {
+	versionA, isVersionA := parseSemanticVersion(a)
+	versionB, isVersionB := parseSemanticVersion(b)
+	switch {
+	case isVersionA && isVersionB:
+		if result := versionA.compare(versionB); result != 0 {
+			return result
+		}
+	case isVersionA:
+		return 1
+	case isVersionB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
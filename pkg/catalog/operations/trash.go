@@ -0,0 +1,195 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/napptive/catalog-cli/v2/internal/pkg/connection"
+	"github.com/napptive/catalog-cli/v2/internal/pkg/trash"
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// removeOperation with the name of the remove operation in the trash entries.
+	removeOperation = "remove"
+	// pruneOperation with the name of the prune operation in the trash entries.
+	pruneOperation = "prune"
+	// visibilityOperation with the name of the change-visibility operation in the trash entries.
+	visibilityOperation = "change-visibility"
+)
+
+// BackupOptions with the options that control the backup of the applications before a destructive operation.
+type BackupOptions struct {
+	// Backup saves the affected application tags in the local trash so they can be restored.
+	Backup bool
+}
+
+// localTrash returns the trash where the applications are saved.
+func (c *Catalog) localTrash() (*trash.Trash, error) {
+	if c.cfg.TrashDir != "" {
+		return trash.New(c.cfg.TrashDir), nil
+	}
+	dir, err := trash.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return trash.New(dir), nil
+}
+
+// backupApplications saves the application tags in a new trash entry. Any failure aborts the backup so
+// that the destructive operation is not executed without it. Entries older than the retention are
+// removed afterwards.
+func (c *Catalog) backupApplications(client grpc_catalog_go.CatalogClient, operation string, catalogURL string, targets []*entities.RemoveTarget) (*entities.TrashEntry, error) {
+	localTrash, err := c.localTrash()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := localTrash.Create(operation, catalogURL)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		bundle, err := c.downloadBundle(client, target.ApplicationID, false, nil)
+		if err == nil {
+			err = localTrash.AddBundle(entry, target.ApplicationID, target.Private, bundle.digest, bundle.archive.Data)
+		}
+		if err != nil {
+			// an incomplete entry cannot be restored
+			if removeErr := localTrash.Remove(entry); removeErr != nil {
+				log.Warn().Err(removeErr).Str("entry", entry.Name).Msg("unable to remove incomplete trash entry")
+			}
+			return nil, nerrors.NewInternalErrorFrom(nerrors.FromGRPC(err), "unable to back up %s, %s has been canceled", target.ApplicationID, operation)
+		}
+	}
+	log.Info().Str("entry", entry.Name).Int("applications", len(entry.Applications)).Str("trash", localTrash.Path()).
+		Msgf("applications saved, use catalog restore %s to restore them", entry.Name)
+
+	if c.cfg.TrashRetention > 0 {
+		if _, err := localTrash.Prune(c.cfg.TrashRetention); err != nil {
+			log.Warn().Err(err).Msg("unable to remove old entries from the trash")
+		}
+	}
+	return entry, nil
+}
+
+// Restore pushes again the application tags saved in a trash entry, with their original visibility.
+func (c *Catalog) Restore(entryName string) error {
+	log.Debug().Str("entry", entryName).Msg("Restore received!")
+	localTrash, err := c.localTrash()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	entry, err := localTrash.Get(entryName)
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	if len(entry.Applications) == 0 {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewFailedPreconditionError("trash entry %s contains no applications", entryName))
+	}
+
+	// All the applications of an entry share the catalog
+	conn, err := connection.GetConnectionToCatalog(&c.cfg.ConnectionConfig, restoreTarget(entry, entry.Applications[0]))
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewInternalErrorFrom(err, "cannot establish connection with catalog-manager server on %s", entry.CatalogURL))
+	}
+	defer conn.Close()
+	client := grpc_catalog_go.NewCatalogClient(conn)
+
+	result := &entities.RestoreResult{Entry: entry.Name, CatalogURL: entry.CatalogURL, Results: make([]*entities.PushResult, 0, len(entry.Applications))}
+	numFailed := 0
+	for _, app := range entry.Applications {
+		pushResult := &entities.PushResult{ApplicationID: app.ApplicationID, Status: entities.PushSuccess}
+		info, err := c.restoreApplication(client, localTrash.BundlePath(entry, app), restoreTarget(entry, app), app)
+		if err != nil {
+			log.Debug().Err(err).Str("applicationID", app.ApplicationID).Msg("restore failed")
+			pushResult.Status = entities.PushFailed
+			pushResult.Info = err.Error()
+			numFailed++
+		} else {
+			pushResult.Info = info
+		}
+		result.Results = append(result.Results, pushResult)
+	}
+	if err := c.ResultPrinter.PrintResultOrError(result, nil); err != nil {
+		return err
+	}
+	if numFailed > 0 {
+		return nerrors.NewInternalError("%d of %d applications could not be restored", numFailed, len(entry.Applications))
+	}
+	return nil
+}
+
+// restoreTarget returns the identifier of a saved application including the catalog it was removed from.
+func restoreTarget(entry *entities.TrashEntry, app *entities.TrashedApplication) string {
+	if entry.CatalogURL == "" {
+		return app.ApplicationID
+	}
+	return fmt.Sprintf("%s/%s", entry.CatalogURL, app.ApplicationID)
+}
+
+// restoreApplication pushes the files of a saved application exactly as they were saved, checking
+// first that their content has not changed. The .catalogignore rules and the symbolic link policy do
+// not apply as the files were received from the catalog.
+func (c *Catalog) restoreApplication(client grpc_catalog_go.CatalogClient, bundlePath string, target string, app *entities.TrashedApplication) (string, error) {
+	content, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return "", nerrors.NewInternalErrorFrom(err, "unable to read %s", bundlePath)
+	}
+	entries, err := archiveFiles(content, bundlePath)
+	if err != nil {
+		return "", err
+	}
+	digest := filesDigest(entries)
+	if app.Digest != "" && digest != app.Digest {
+		return "", nerrors.NewFailedPreconditionError("the saved content of %s is corrupted, expected digest %s, found %s", app.ApplicationID, app.Digest, digest)
+	}
+	files := make([]appFile, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, appFile{name: entry.Path, size: int64(len(entry.Data)), data: entry.Data})
+	}
+	reply, _, err := c.pushApplication(client, target, "", files, digest, &PushOptions{Private: app.Private, Force: true})
+	if err != nil {
+		return "", nerrors.FromGRPC(err)
+	}
+	return reply.UserInfo, nil
+}
+
+// TrashList prints the entries of the trash.
+func (c *Catalog) TrashList() error {
+	localTrash, err := c.localTrash()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	return c.ResultPrinter.PrintResultOrError(localTrash.List())
+}
+
+// TrashPrune removes the entries of the trash older than the retention.
+func (c *Catalog) TrashPrune() error {
+	if c.cfg.TrashRetention <= 0 {
+		return c.ResultPrinter.PrintResultOrError(nil, nerrors.NewFailedPreconditionError("the trash retention is disabled, use --trashRetention to set it"))
+	}
+	localTrash, err := c.localTrash()
+	if err != nil {
+		return c.ResultPrinter.PrintResultOrError(nil, err)
+	}
+	return c.ResultPrinter.PrintResultOrError(localTrash.Prune(c.cfg.TrashRetention))
+}
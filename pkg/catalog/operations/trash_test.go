@@ -0,0 +1,127 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+
+	"github.com/napptive/catalog-cli/v2/pkg/catalog/entities"
+	"github.com/napptive/catalog-cli/v2/pkg/config"
+	grpc_catalog_go "github.com/napptive/grpc-catalog-go"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Trash tests", func() {
+
+	var trashDir string
+	var catalog *Catalog
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-trash")
+		gomega.Expect(err).To(gomega.Succeed())
+		trashDir = dir
+		cfg := &config.Config{
+			ConnectionConfig: config.ConnectionConfig{CatalogAddress: "catalog", CatalogPort: 7060},
+			TrashDir:         trashDir,
+		}
+		catalog = &Catalog{cfg: cfg, AuthToken: config.NewAuthToken(cfg)}
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(trashDir)
+	})
+
+	ginkgo.It("Should restore the applications in the catalog they were removed from", func() {
+		app := &entities.TrashedApplication{ApplicationID: "ns/app:1.0"}
+		gomega.Expect(restoreTarget(&entities.TrashEntry{CatalogURL: "catalog.example.com:7060"}, app)).To(gomega.Equal("catalog.example.com:7060/ns/app:1.0"))
+		gomega.Expect(restoreTarget(&entities.TrashEntry{}, app)).To(gomega.Equal("ns/app:1.0"))
+	})
+
+	ginkgo.It("Should refuse to restore a corrupted application", func() {
+		localTrash, err := catalog.localTrash()
+		gomega.Expect(err).To(gomega.Succeed())
+		entry, err := localTrash.Create(removeOperation, "catalog:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+		data := createTestArchive(map[string]string{"./metadata.yaml": "kind: ApplicationMetadata"})
+		gomega.Expect(localTrash.AddBundle(entry, "ns/app:1.0", false, "sha256:0000", data)).To(gomega.Succeed())
+
+		_, err = catalog.restoreApplication(nil, localTrash.BundlePath(entry, entry.Applications[0]), "ns/app:1.0", entry.Applications[0])
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("corrupted"))
+	})
+
+	ginkgo.It("Should push the saved files exactly as they were received", func() {
+		localTrash, err := catalog.localTrash()
+		gomega.Expect(err).To(gomega.Succeed())
+		entry, err := localTrash.Create(removeOperation, "catalog:7060")
+		gomega.Expect(err).To(gomega.Succeed())
+		saved := map[string]string{
+			"./metadata.yaml":   "kind: ApplicationMetadata",
+			"./.catalogignore":  "*.log",
+			"./debug.log":       "kept in the catalog",
+			"./.git/config":     "[core]",
+			"./conf/empty.yaml": "",
+		}
+		data := createTestArchive(saved)
+		digest, err := archiveDigest(data, "app.tgz")
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(localTrash.AddBundle(entry, "ns/app:1.0", true, digest, data)).To(gomega.Succeed())
+
+		client := &fakeCatalogClient{}
+		info, err := catalog.restoreApplication(client, localTrash.BundlePath(entry, entry.Applications[0]), "ns/app:1.0", entry.Applications[0])
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(info).To(gomega.Equal("application added"))
+
+		pushed := make([]*grpc_catalog_go.FileInfo, 0, len(client.added))
+		received := map[string]string{}
+		for _, request := range client.added {
+			gomega.Expect(request.ApplicationId).To(gomega.Equal("ns/app:1.0"))
+			gomega.Expect(request.Private).To(gomega.BeTrue())
+			pushed = append(pushed, request.File)
+			received[request.File.Path] = string(request.File.Data)
+		}
+		gomega.Expect(received).To(gomega.Equal(saved))
+		gomega.Expect(filesDigest(pushed)).To(gomega.Equal(digest))
+	})
+
+	ginkgo.It("Should save the applications before removing them", func() {
+		client := &fakeCatalogClient{}
+		targets := []*entities.RemoveTarget{{ApplicationID: "ns/app:1.0", Private: true}, {ApplicationID: "ns/app:latest"}}
+		entry, err := catalog.backupApplications(client, removeOperation, "catalog:7060", targets)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(entry.Applications).To(gomega.HaveLen(2))
+		gomega.Expect(entry.Applications[0].Private).To(gomega.BeTrue())
+
+		localTrash, err := catalog.localTrash()
+		gomega.Expect(err).To(gomega.Succeed())
+		_, err = os.Stat(localTrash.BundlePath(entry, entry.Applications[1]))
+		gomega.Expect(err).To(gomega.Succeed())
+	})
+
+	ginkgo.It("Should not leave an incomplete entry if the backup fails", func() {
+		client := &fakeCatalogClient{failing: map[string]bool{"ns/app:latest": true}}
+		targets := []*entities.RemoveTarget{{ApplicationID: "ns/app:1.0"}, {ApplicationID: "ns/app:latest"}}
+		_, err := catalog.backupApplications(client, removeOperation, "catalog:7060", targets)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("ns/app:latest"))
+
+		entries, err := os.ReadDir(trashDir)
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(entries).To(gomega.BeEmpty())
+	})
+})
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/napptive/nerrors/pkg/nerrors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// SymlinksFollow includes the target of the symbolic links.
+	SymlinksFollow = "follow"
+	// SymlinksSkip ignores the symbolic links.
+	SymlinksSkip = "skip"
+	// SymlinksError fails if a symbolic link is found.
+	SymlinksError = "error"
+)
+
+// WalkOptions with the options used to read the files of an application directory.
+type WalkOptions struct {
+	// Symlinks with the policy applied to symbolic links: follow, skip or error.
+	Symlinks string
+	// AllowExternalSymlinks permits following links whose target is outside the application directory.
+	AllowExternalSymlinks bool
+}
+
+// appWalker with the state required to read an application directory.
+type appWalker struct {
+	WalkOptions
+	// rules with the files to be ignored.
+	rules *ignoreRules
+	// root with the real path of the application directory.
+	root string
+	// active with the real paths of the directories being read, used to detect loops.
+	active map[string]bool
+}
+
+// newAppWalker creates an appWalker for the given application directory.
+func newAppWalker(appPath string, rules *ignoreRules, opts WalkOptions) (*appWalker, error) {
+	if opts.Symlinks == "" {
+		opts.Symlinks = SymlinksFollow
+	}
+	if opts.Symlinks != SymlinksFollow && opts.Symlinks != SymlinksSkip && opts.Symlinks != SymlinksError {
+		return nil, nerrors.NewInvalidArgumentError("invalid symlinks policy %q, use %s, %s or %s", opts.Symlinks, SymlinksFollow, SymlinksSkip, SymlinksError)
+	}
+	root, err := filepath.Abs(appPath)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, nerrors.NewInvalidArgumentError("unable to open directory %s. Check that the path is correct, it is accessible by the current user, and it contains an application file", appPath)
+	}
+	return &appWalker{
+		WalkOptions: opts,
+		rules:       rules,
+		root:        root,
+		active:      make(map[string]bool),
+	}, nil
+}
+
+// readApp reads the files of an application directory applying the .catalogignore rules.
+func (c *Catalog) readApp(appPath string, opts WalkOptions) ([]string, error) {
+	rules, err := loadIgnoreRules(appPath)
+	if err != nil {
+		return nil, err
+	}
+	walker, err := newAppWalker(appPath, rules, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.loadApp(appPath, ".", walker)
+}
+
+// ignored checks if an entry matches the ignore rules logging the decision.
+func (w *appWalker) ignored(relativePath string, isDir bool) bool {
+	ignored, rule := w.rules.match(relativePath, isDir)
+	if ignored {
+		log.Debug().Str("path", relativePath).Str("pattern", rule.pattern).Str("source", rule.source).Msg("file ignored")
+	} else if rule != nil {
+		log.Debug().Str("path", relativePath).Str("pattern", rule.pattern).Str("source", rule.source).Msg("file included by negated pattern")
+	}
+	return ignored
+}
+
+// resolve returns the information of a directory entry applying the symbolic link policy. A nil
+// result means that the entry must be skipped.
+func (w *appWalker) resolve(path string, relativePath string, info os.FileInfo) (os.FileInfo, error) {
+	if info.Mode()&os.ModeSymlink == 0 {
+		return info, nil
+	}
+	switch w.Symlinks {
+	case SymlinksSkip:
+		log.Debug().Str("path", relativePath).Msg("symbolic link skipped")
+		return nil, nil
+	case SymlinksError:
+		return nil, nerrors.NewFailedPreconditionError("symbolic link %s found, use --symlinks to follow or skip links", relativePath)
+	}
+
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, nerrors.NewFailedPreconditionError("unable to resolve symbolic link %s: %s", relativePath, err.Error())
+	}
+	if !w.AllowExternalSymlinks && !isWithin(w.root, target) {
+		return nil, nerrors.NewFailedPreconditionError("symbolic link %s points to %s, outside the application directory. Use --allow-external-symlinks to include it", relativePath, target)
+	}
+	log.Debug().Str("path", relativePath).Str("target", target).Msg("following symbolic link")
+	return os.Stat(target)
+}
+
+// enter registers that a directory is being read. It fails if the directory is already being read,
+// which means that a symbolic link creates a loop.
+func (w *appWalker) enter(path string, relativePath string) (string, error) {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", nerrors.NewInternalErrorFrom(err, "unable to resolve directory %s", relativePath)
+	}
+	if w.active[realPath] {
+		return "", nerrors.NewFailedPreconditionError("symbolic link loop detected in %s", relativePath)
+	}
+	w.active[realPath] = true
+	return realPath, nil
+}
+
+// leave registers that a directory has been read.
+func (w *appWalker) leave(realPath string) {
+	delete(w.active, realPath)
+}
+
+// isWithin checks if a path is inside a directory.
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
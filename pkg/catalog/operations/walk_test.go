@@ -0,0 +1,78 @@
+/**
+ * Copyright 2023 Napptive
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Application walk tests", func() {
+
+	var baseDir string
+	var appDir string
+
+	ginkgo.BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "catalog-walk")
+		gomega.Expect(err).To(gomega.Succeed())
+		baseDir = dir
+		appDir = filepath.Join(baseDir, "app")
+		gomega.Expect(os.MkdirAll(filepath.Join(appDir, "config"), 0755)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(appDir, "config", "app.yaml"), []byte("app"), 0644)).To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(baseDir, "secret"), []byte("secret"), 0644)).To(gomega.Succeed())
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(baseDir)
+	})
+
+	ginkgo.It("Should follow links inside the application directory", func() {
+		gomega.Expect(os.Symlink(filepath.Join(appDir, "config", "app.yaml"), filepath.Join(appDir, "link.yaml"))).To(gomega.Succeed())
+		names, err := (&Catalog{}).readApp(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.Succeed())
+		sort.Strings(names)
+		gomega.Expect(names).To(gomega.Equal([]string{"./config/app.yaml", "./link.yaml"}))
+	})
+
+	ginkgo.It("Should refuse links outside the application directory unless allowed", func() {
+		gomega.Expect(os.Symlink(filepath.Join(baseDir, "secret"), filepath.Join(appDir, "secret"))).To(gomega.Succeed())
+		_, err := (&Catalog{}).readApp(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		names, err := (&Catalog{}).readApp(appDir, WalkOptions{AllowExternalSymlinks: true})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(names).To(gomega.HaveLen(2))
+	})
+
+	ginkgo.It("Should detect symbolic link loops", func() {
+		gomega.Expect(os.Symlink(appDir, filepath.Join(appDir, "config", "loop"))).To(gomega.Succeed())
+		_, err := (&Catalog{}).readApp(appDir, WalkOptions{})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("Should apply the skip and error policies", func() {
+		gomega.Expect(os.Symlink(filepath.Join(appDir, "config"), filepath.Join(appDir, "other"))).To(gomega.Succeed())
+		names, err := (&Catalog{}).readApp(appDir, WalkOptions{Symlinks: SymlinksSkip})
+		gomega.Expect(err).To(gomega.Succeed())
+		gomega.Expect(names).To(gomega.Equal([]string{"./config/app.yaml"}))
+		_, err = (&Catalog{}).readApp(appDir, WalkOptions{Symlinks: SymlinksError})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
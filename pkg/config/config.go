@@ -18,6 +18,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -32,6 +33,12 @@ type Config struct {
 	UsePlaygroundConfiguration bool
 	// PrinterType defines how results are to be shown.
 	PrinterType string
+	// CacheDir with the directory of the local cache of applications. If empty, the default location is used.
+	CacheDir string
+	// TrashDir with the directory where the applications are saved before removing them. If empty, the default location is used.
+	TrashDir string
+	// TrashRetention with the time the entries of the trash are kept. Older entries are removed on each backup.
+	TrashRetention time.Duration
 }
 
 // IsValid checks if the configuration options are valid.